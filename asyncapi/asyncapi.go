@@ -0,0 +1,271 @@
+// Package asyncapi exports the websocket operations of an OpenAPI document,
+// the ones carrying x-websocket, as an AsyncAPI 2 document.
+// Message payloads reference the OpenAPI component schemas, copied as they are.
+package asyncapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+const (
+	// Version - AsyncAPI version of the export
+	Version = "2.6.0"
+	// SchemaFormat - payloads are OpenAPI schemas, nullable and readOnly included
+	SchemaFormat = "application/vnd.oai.openapi;version=3.0.0"
+)
+
+// headers the websocket client sends itself, left out of the bindings
+var handshakeHeaders = map[string]bool{
+	"Connection":            true,
+	"Upgrade":               true,
+	"Sec-WebSocket-Version": true,
+	"Sec-WebSocket-Key":     true,
+}
+
+// AsyncAPI - https://www.asyncapi.com/docs/reference/specification/v2.6.0#A2SObject
+type AsyncAPI struct {
+	AsyncAPI   string             `json:"asyncapi"`
+	Info       openapi.Info       `json:"info"`
+	Servers    map[string]Server  `json:"servers,omitempty"`
+	Channels   map[string]Channel `json:"channels"`
+	Components Components         `json:"components"`
+}
+
+// Server - https://www.asyncapi.com/docs/reference/specification/v2.6.0#serverObject
+type Server struct {
+	URL         string                            `json:"url"`
+	Protocol    string                            `json:"protocol"`
+	Description string                            `json:"description,omitempty"`
+	Variables   map[string]openapi.ServerVariable `json:"variables,omitempty"`
+	Security    []map[string][]string             `json:"security,omitempty"`
+}
+
+// Channel - https://www.asyncapi.com/docs/reference/specification/v2.6.0#channelItemObject
+type Channel struct {
+	Description string               `json:"description,omitempty"`
+	Parameters  map[string]Parameter `json:"parameters,omitempty"`
+	Subscribe   *Operation           `json:"subscribe,omitempty"`
+	Publish     *Operation           `json:"publish,omitempty"`
+	Bindings    *ChannelBindings     `json:"bindings,omitempty"`
+}
+
+// Parameter - https://www.asyncapi.com/docs/reference/specification/v2.6.0#parameterObject
+type Parameter struct {
+	Description string          `json:"description,omitempty"`
+	Schema      *openapi.Schema `json:"schema,omitempty"`
+}
+
+// Operation - https://www.asyncapi.com/docs/reference/specification/v2.6.0#operationObject
+type Operation struct {
+	Summary string   `json:"summary,omitempty"`
+	Tags    []Tag    `json:"tags,omitempty"`
+	Message *Message `json:"message"`
+}
+
+// Tag - https://www.asyncapi.com/docs/reference/specification/v2.6.0#tagObject
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Message - https://www.asyncapi.com/docs/reference/specification/v2.6.0#messageObject
+type Message struct {
+	Ref          string          `json:"$ref,omitempty"`
+	OneOf        []Message       `json:"oneOf,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	Summary      string          `json:"summary,omitempty"`
+	SchemaFormat string          `json:"schemaFormat,omitempty"`
+	Payload      *openapi.Schema `json:"payload,omitempty"`
+}
+
+// ChannelBindings - https://github.com/asyncapi/bindings/tree/master/websockets
+type ChannelBindings struct {
+	WS WebsocketsBinding `json:"ws"`
+}
+
+// WebsocketsBinding - the handshake of a websockets channel
+type WebsocketsBinding struct {
+	Method         string          `json:"method,omitempty"`
+	Query          *openapi.Schema `json:"query,omitempty"`
+	Headers        *openapi.Schema `json:"headers,omitempty"`
+	BindingVersion string          `json:"bindingVersion"`
+}
+
+// Components - https://www.asyncapi.com/docs/reference/specification/v2.6.0#componentsObject
+type Components struct {
+	Schemas         map[string]openapi.Schema         `json:"schemas,omitempty"`
+	Messages        map[string]Message                `json:"messages,omitempty"`
+	SecuritySchemes map[string]openapi.SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// Write - write the AsyncAPI document of swagger to dir/asyncapi.json
+func Write(swagger *openapi.OpenAPI, dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(Convert(swagger), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "asyncapi.json"), out, 0644)
+}
+
+// Convert - a channel for every websocket operation of swagger.
+// Messages the server sends are subscribed to, the ones the client sends published.
+func Convert(swagger *openapi.OpenAPI) *AsyncAPI {
+	doc := &AsyncAPI{
+		AsyncAPI: Version,
+		Info: openapi.Info{
+			Title:       fmt.Sprintf("%s Websockets", swagger.Info.Title),
+			Description: swagger.Info.Description,
+			Version:     swagger.Info.Version,
+		},
+		Servers:  make(map[string]Server),
+		Channels: make(map[string]Channel),
+		Components: Components{
+			Schemas:         swagger.Components.Schemas,
+			Messages:        make(map[string]Message),
+			SecuritySchemes: swagger.Components.SecuritySchemes,
+		},
+	}
+
+	for i, server := range swagger.Servers {
+		name := "rancher"
+		if i > 0 {
+			name = fmt.Sprintf("rancher%d", i+1)
+		}
+		doc.Servers[name] = websocketServer(server, swagger.Security)
+	}
+
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		op := pathItem.Get
+		if op == nil || op.Websocket() == nil {
+			continue
+		}
+		ws := op.Websocket()
+		channel := Channel{
+			Description: op.Description,
+			Parameters:  make(map[string]Parameter),
+			Bindings: &ChannelBindings{
+				WS: WebsocketsBinding{
+					Method:         "GET",
+					BindingVersion: "0.1.0",
+				},
+			},
+		}
+
+		queryParams := &openapi.Schema{Type: "object", Properties: make(map[string]openapi.Schema)}
+		headers := &openapi.Schema{Type: "object", Properties: make(map[string]openapi.Schema)}
+		for key, value := range ws.Query {
+			queryParams.Properties[key] = openapi.Schema{Type: "string", Enum: []interface{}{value}}
+			queryParams.Required = append(queryParams.Required, key)
+		}
+		for _, param := range swagger.Parameters(pathItem, op) {
+			if op.Selects(param) {
+				continue
+			}
+			schema := openapi.Schema{Type: "string", Description: param.Description}
+			if param.Schema != nil {
+				schema = *param.Schema
+				if schema.Description == "" {
+					schema.Description = param.Description
+				}
+			}
+			switch {
+			case param.In == "path":
+				channel.Parameters[param.Name] = Parameter{Description: param.Description, Schema: &schema}
+			case param.In == "query":
+				queryParams.Properties[param.Name] = schema
+				if param.Required {
+					queryParams.Required = append(queryParams.Required, param.Name)
+				}
+			case param.In == "header" && !handshakeHeaders[param.Name]:
+				headers.Properties[param.Name] = schema
+				// optional on a GET that is only upgraded with its query
+				if param.Required || !op.Upgrades() {
+					headers.Required = append(headers.Required, param.Name)
+				}
+			}
+		}
+		if len(queryParams.Properties) > 0 {
+			sort.Strings(queryParams.Required)
+			channel.Bindings.WS.Query = queryParams
+		}
+		if len(headers.Properties) > 0 {
+			channel.Bindings.WS.Headers = headers
+		}
+		if len(channel.Parameters) == 0 {
+			channel.Parameters = nil
+		}
+
+		channel.Subscribe = doc.operation(op, ws.Receive)
+		channel.Publish = doc.operation(op, ws.Send)
+		doc.Channels[path] = channel
+	}
+	return doc
+}
+
+// operation - an operation sending or receiving messages, each message is
+// added to the components once and referenced
+func (doc *AsyncAPI) operation(op *openapi.Operation, messages []openapi.Message) *Operation {
+	if len(messages) == 0 {
+		return nil
+	}
+	refs := make([]Message, 0, len(messages))
+	for _, m := range messages {
+		if _, ok := doc.Components.Messages[m.Name]; !ok {
+			doc.Components.Messages[m.Name] = Message{
+				Name:         m.Name,
+				Summary:      m.Summary,
+				SchemaFormat: SchemaFormat,
+				Payload:      m.Payload,
+			}
+		}
+		refs = append(refs, Message{Ref: fmt.Sprintf("#/components/messages/%s", m.Name)})
+	}
+
+	operation := &Operation{
+		Summary: op.Summary,
+		Message: &refs[0],
+	}
+	if len(refs) > 1 {
+		operation.Message = &Message{OneOf: refs}
+	}
+	for _, tag := range op.Tags {
+		operation.Tags = append(operation.Tags, Tag{Name: tag})
+	}
+	return operation
+}
+
+// websocketServer - the server with a ws or wss URL
+func websocketServer(server openapi.Server, security []map[string][]string) Server {
+	url, protocol := server.URL, "wss"
+	switch {
+	case strings.HasPrefix(url, "https://"):
+		url = "wss://" + strings.TrimPrefix(url, "https://")
+	case strings.HasPrefix(url, "http://"):
+		url, protocol = "ws://"+strings.TrimPrefix(url, "http://"), "ws"
+	}
+	return Server{
+		URL:         url,
+		Protocol:    protocol,
+		Description: server.Description,
+		Variables:   server.Variables,
+		Security:    security,
+	}
+}
@@ -0,0 +1,139 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func testSwagger() *openapi.OpenAPI {
+	event := &openapi.Schema{Ref: "#/components/schemas/subscribeEvent"}
+	return &openapi.OpenAPI{
+		Info:     openapi.Info{Title: "Rancher API", Version: "v3"},
+		Servers:  []openapi.Server{{URL: "https://{hostname}/v3"}},
+		Security: []map[string][]string{{"bearer": {}}},
+		Paths: map[string]openapi.PathItem{
+			"/clusters": {
+				Get: &openapi.Operation{Tags: []string{"cluster"}},
+			},
+			"/subscribe": {
+				Get: &openapi.Operation{
+					Tags:        []string{"subscribe"},
+					Description: "Stream changes",
+					Parameters: []openapi.Parameter{
+						{Name: "Upgrade", In: "header", Required: true},
+						{Name: "eventNames", In: "query", Schema: &openapi.Schema{Type: "array"}},
+					},
+					Extensions: openapi.Extensions{"x-websocket": &openapi.Websocket{
+						Receive: []openapi.Message{
+							{Name: "resource.change", Payload: event},
+							{Name: "ping", Payload: event},
+						},
+					}},
+				},
+			},
+			"/clusters/{clusterId}": {
+				Parameters: []openapi.Parameter{{Ref: "#/components/parameters/clusterId"}},
+				Get: &openapi.Operation{
+					Tags: []string{"cluster"},
+					Parameters: []openapi.Parameter{
+						{Name: "shell", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"true"}}},
+						{Name: "Sec-WebSocket-Protocol", In: "header", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"base64.channel.k8s.io"}}},
+					},
+					Extensions: openapi.Extensions{"x-websocket": &openapi.Websocket{
+						Query:   map[string]string{"shell": "true"},
+						Send:    []openapi.Message{{Name: "stdin", Payload: &openapi.Schema{Type: "string"}}},
+						Receive: []openapi.Message{{Name: "stdout", Payload: &openapi.Schema{Type: "string"}}},
+					}},
+				},
+			},
+		},
+		Components: openapi.Components{
+			Parameters: map[string]openapi.Parameter{
+				"clusterId": {Name: "clusterId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+			},
+			Schemas: map[string]openapi.Schema{
+				"subscribeEvent": {Type: "object"},
+			},
+		},
+	}
+}
+
+func TestConvert(t *testing.T) {
+	doc := Convert(testSwagger())
+
+	if doc.AsyncAPI != Version || doc.Info.Title != "Rancher API Websockets" {
+		t.Errorf("unexpected header %+v", doc)
+	}
+	if server := doc.Servers["rancher"]; server.URL != "wss://{hostname}/v3" || server.Protocol != "wss" || len(server.Security) != 1 {
+		t.Errorf("expected a secured wss server, got %+v", server)
+	}
+	if len(doc.Channels) != 2 {
+		t.Fatalf("expected the subscribe and shell channels, got %+v", doc.Channels)
+	}
+
+	subscribe := doc.Channels["/subscribe"]
+	if subscribe.Publish != nil || subscribe.Subscribe == nil || len(subscribe.Subscribe.Message.OneOf) != 2 {
+		t.Fatalf("expected to receive one of two messages on /subscribe, got %+v", subscribe)
+	}
+	if ref := subscribe.Subscribe.Message.OneOf[0].Ref; ref != "#/components/messages/resource.change" {
+		t.Errorf("expected a message reference, got %s", ref)
+	}
+	if query := subscribe.Bindings.WS.Query; query == nil || query.Properties["eventNames"].Type != "array" {
+		t.Errorf("expected eventNames in the query binding, got %+v", query)
+	}
+	if subscribe.Bindings.WS.Headers != nil {
+		t.Errorf("expected no handshake headers in the binding, got %+v", subscribe.Bindings.WS.Headers)
+	}
+	if payload := doc.Components.Messages["ping"].Payload; payload == nil || payload.Ref != "#/components/schemas/subscribeEvent" {
+		t.Errorf("expected ping to reference subscribeEvent, got %+v", payload)
+	}
+	if _, ok := doc.Components.Schemas["subscribeEvent"]; !ok {
+		t.Error("expected the component schemas")
+	}
+
+	shell, ok := doc.Channels["/clusters/{clusterId}"]
+	if !ok {
+		t.Fatalf("expected the shell channel on the cluster path, got %+v", doc.Channels)
+	}
+	if shell.Publish == nil || shell.Publish.Message.Ref != "#/components/messages/stdin" {
+		t.Errorf("expected to publish stdin, got %+v", shell.Publish)
+	}
+	if _, ok := shell.Parameters["clusterId"]; !ok {
+		t.Errorf("expected the clusterId parameter, got %+v", shell.Parameters)
+	}
+	if query := shell.Bindings.WS.Query; query == nil || len(query.Required) != 1 || query.Properties["shell"].Enum[0] != "true" {
+		t.Errorf("expected shell=true in the query binding, got %+v", query)
+	}
+	if headers := shell.Bindings.WS.Headers; headers == nil || headers.Properties["Sec-WebSocket-Protocol"].Enum[0] != "base64.channel.k8s.io" || len(headers.Required) != 1 {
+		t.Errorf("expected the subprotocol header, got %+v", headers)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen-api-docs-asyncapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = Write(testSwagger(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ioutil.ReadFile(filepath.Join(dir, "asyncapi.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["asyncapi"] != Version {
+		t.Errorf("unexpected document %s", out)
+	}
+}
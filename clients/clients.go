@@ -0,0 +1,256 @@
+// Package clients exports the generated operations as Postman and Insomnia
+// collections, ready to import into either client.
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	"github.com/rancher/gen-api-docs/render"
+)
+
+// TokenVariable - variable holding the API token in the exported environments
+const TokenVariable = "token"
+
+var pathParams = regexp.MustCompile("{(\\w+)}")
+
+// Folder - requests for a tag, split into one sub folder per collection path
+type Folder struct {
+	Name     string
+	Folders  []*Folder
+	Requests []Request
+}
+
+// Request - an operation with everything needed to send it
+type Request struct {
+	Name        string
+	Description string
+	Method      string
+	// ServerURL - the path's own server, server variables as {name}.
+	// Empty for paths relative to the Spec's ServerURL.
+	ServerURL string
+	// Path relative to the server URL, path parameters as {name}
+	Path       string
+	PathParams []Param
+	Query      []Param
+	Body       string
+	// NoAuth - the operation is unauthenticated, the token isn't sent
+	NoAuth bool
+}
+
+// Param - a path or query parameter.
+// Fixed parameters, like the action of an action request, are always sent.
+type Param struct {
+	Name        string
+	Value       string
+	Description string
+	Fixed       bool
+}
+
+// Variable - an environment variable, from the server variables and the token
+type Variable struct {
+	Name        string
+	Value       string
+	Description string
+}
+
+// Spec - the operations of an OpenAPI doc grouped for export
+type Spec struct {
+	Name        string
+	Description string
+	// Server URL, server variables as {name}
+	ServerURL string
+	Variables []Variable
+	Folders   []*Folder
+}
+
+// NewSpec - group the operations by tag, then collection path
+func NewSpec(swagger *openapi.OpenAPI) *Spec {
+	spec := &Spec{
+		Name:        swagger.Info.Title,
+		Description: swagger.Info.Description,
+	}
+
+	if len(swagger.Servers) > 0 {
+		server := swagger.Servers[0]
+		spec.ServerURL = server.URL
+		names := make([]string, 0, len(server.Variables))
+		for name := range server.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			spec.Variables = append(spec.Variables, Variable{
+				Name:        name,
+				Value:       server.Variables[name].Default,
+				Description: server.Variables[name].Description,
+			})
+		}
+	}
+	spec.Variables = append(spec.Variables, Variable{
+		Name:        TokenVariable,
+		Description: "Rancher API token",
+	})
+
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	tags := make(map[string]*Folder)
+	collections := make(map[string]*Folder)
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		for _, method := range openapi.Methods {
+			op := pathItem.Operation(method)
+			// Websockets can't be sent as plain requests, see the asyncapi export
+			if op == nil || op.Upgrades() {
+				continue
+			}
+			tag := "default"
+			if len(op.Tags) > 0 {
+				tag = op.Tags[0]
+			}
+			folder, ok := tags[tag]
+			if !ok {
+				folder = &Folder{Name: tag}
+				tags[tag] = folder
+				spec.Folders = append(spec.Folders, folder)
+			}
+
+			colPath := collectionPath(path)
+			sub, ok := collections[tag+colPath]
+			if !ok {
+				sub = &Folder{Name: colPath}
+				collections[tag+colPath] = sub
+				folder.Folders = append(folder.Folders, sub)
+			}
+			sub.Requests = append(sub.Requests, newRequests(swagger, path, method, pathItem, op)...)
+		}
+	}
+
+	sort.Slice(spec.Folders, func(i, j int) bool {
+		return spec.Folders[i].Name < spec.Folders[j].Name
+	})
+	return spec
+}
+
+// newRequests - the request for an operation, or one for each of its actions.
+// A POST that also creates without an action keeps its own request.
+func newRequests(swagger *openapi.OpenAPI, path string, method string, pathItem openapi.PathItem, op *openapi.Operation) []Request {
+	r := newRequest(swagger, path, method, pathItem, op)
+	if len(op.Actions()) == 0 {
+		return []Request{r}
+	}
+
+	requests := make([]Request, 0, len(op.Actions())+1)
+	if !op.ActionRequired() {
+		requests = append(requests, r)
+	}
+
+	names := make([]string, 0, len(op.Actions()))
+	for name := range op.Actions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		action := op.Actions()[name]
+		a := r
+		a.Name = fmt.Sprintf("%s %s?action=%s", name, path, name)
+		a.Description = action.Description
+		a.Query = append([]Param{{Name: "action", Value: name, Fixed: true}}, r.Query...)
+		a.Body = ""
+		if action.Input != nil {
+			a.Body = body(swagger, *action.Input)
+		}
+		requests = append(requests, a)
+	}
+	return requests
+}
+
+func newRequest(swagger *openapi.OpenAPI, path string, method string, pathItem openapi.PathItem, op *openapi.Operation) Request {
+	r := Request{
+		Name:        fmt.Sprintf("%s %s", method, path),
+		Description: op.Description,
+		Method:      method,
+		Path:        path,
+	}
+	if op.Summary != "" {
+		r.Name = fmt.Sprintf("%s %s", op.Summary, path)
+	}
+	if len(pathItem.Servers) > 0 {
+		r.ServerURL = pathItem.Servers[0].URL
+	}
+	if op.Security != nil && len(*op.Security) == 0 {
+		r.NoAuth = true
+	}
+
+	for _, param := range swagger.Parameters(pathItem, op) {
+		// set by the request of each action, or opens the websocket of a GET
+		if op.Selects(param) {
+			continue
+		}
+		p := Param{
+			Name:        param.Name,
+			Description: param.Description,
+		}
+		switch param.In {
+		case "path":
+			r.PathParams = append(r.PathParams, p)
+		case "query":
+			r.Query = append(r.Query, p)
+		}
+	}
+
+	if op.RequestBody != nil {
+		for _, mediaType := range op.RequestBody.Content {
+			if mediaType.Schema == nil {
+				continue
+			}
+			r.Body = body(swagger, *mediaType.Schema)
+			break
+		}
+	}
+
+	return r
+}
+
+// body - JSON request example of schema
+func body(swagger *openapi.OpenAPI, schema openapi.Schema) string {
+	out, err := json.MarshalIndent(render.Example(swagger, schema, true), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// collectionPath - path of the collection an operation belongs to
+// /clusters/{clusterId} -> /clusters
+func collectionPath(path string) string {
+	segments := strings.Split(path, "/")
+	if len(segments) > 2 && pathParams.MatchString(segments[len(segments)-1]) {
+		segments = segments[:len(segments)-1]
+	}
+	return strings.Join(segments, "/")
+}
+
+// substitute - replace {name} with the client's variable syntax
+func substitute(s string, variable func(string) string) string {
+	return pathParams.ReplaceAllStringFunc(s, func(m string) string {
+		return variable(m[1 : len(m)-1])
+	})
+}
+
+func writeJSON(file string, v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, out, 0644)
+}
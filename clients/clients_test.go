@@ -0,0 +1,190 @@
+package clients
+
+import (
+	"strings"
+	"testing"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func testSwagger() *openapi.OpenAPI {
+	return &openapi.OpenAPI{
+		Info: openapi.Info{Title: "Rancher API"},
+		Servers: []openapi.Server{{
+			URL: "https://{hostname}/v3",
+			Variables: map[string]openapi.ServerVariable{
+				"hostname": {Default: "rancher.example.com", Description: "Hostname of your Rancher Server"},
+			},
+		}},
+		Paths: map[string]openapi.PathItem{
+			"/clusters": {
+				Get: &openapi.Operation{
+					Tags:       []string{"cluster"},
+					Parameters: []openapi.Parameter{{Name: "name", In: "query"}},
+				},
+				Post: &openapi.Operation{
+					Tags: []string{"cluster"},
+					RequestBody: &openapi.RequestBody{
+						Content: map[string]openapi.MediaType{
+							"application/json": {Schema: &openapi.Schema{Ref: "#/components/schemas/cluster"}},
+						},
+					},
+				},
+			},
+			"/clusters/{clusterId}": {
+				Parameters: []openapi.Parameter{{Ref: "#/components/parameters/clusterId"}},
+				Get: &openapi.Operation{
+					Tags: []string{"cluster"},
+					Parameters: []openapi.Parameter{
+						{Name: "shell", In: "query"},
+						{Name: "Upgrade", In: "header"},
+					},
+					Extensions: openapi.Extensions{"x-websocket": &openapi.Websocket{Query: map[string]string{"shell": "true"}}},
+				},
+				Post: &openapi.Operation{
+					Tags:       []string{"cluster"},
+					Parameters: []openapi.Parameter{{Name: "action", In: "query", Required: true}},
+					Extensions: openapi.Extensions{"x-rancher-actions": map[string]openapi.Action{"generateKubeconfig": {}}},
+				},
+			},
+			"/clusters/{clusterId}/nodes": {
+				Parameters: []openapi.Parameter{{Ref: "#/components/parameters/clusterId"}},
+				Get:        &openapi.Operation{Tags: []string{"node"}},
+			},
+			"/subscribe": {
+				Get: &openapi.Operation{Tags: []string{"subscribe"}, Extensions: openapi.Extensions{"x-websocket": &openapi.Websocket{}}},
+			},
+		},
+		Components: openapi.Components{
+			Parameters: map[string]openapi.Parameter{
+				"clusterId": {Name: "clusterId", In: "path", Required: true},
+			},
+			Schemas: map[string]openapi.Schema{
+				"cluster": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"name":  {Type: "string"},
+						"state": {Type: "string", ReadOnly: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNewSpec(t *testing.T) {
+	spec := NewSpec(testSwagger())
+
+	if len(spec.Folders) != 2 || spec.Folders[0].Name != "cluster" || spec.Folders[1].Name != "node" {
+		t.Fatalf("expected cluster and node folders without the websocket, got %+v", spec.Folders)
+	}
+	cluster := spec.Folders[0]
+	if len(cluster.Folders) != 1 || cluster.Folders[0].Name != "/clusters" || len(cluster.Folders[0].Requests) != 4 {
+		t.Fatalf("expected the cluster requests in a /clusters folder, got %+v", cluster.Folders)
+	}
+	create := cluster.Folders[0].Requests[1]
+	if create.Method != "POST" || !strings.Contains(create.Body, `"name"`) || strings.Contains(create.Body, `"state"`) {
+		t.Errorf("expected create body without read only fields, got %+v", create)
+	}
+	if get := cluster.Folders[0].Requests[2]; get.Method != "GET" || len(get.Query) != 0 {
+		t.Errorf("expected the GET of a cluster without the query of its websocket, got %+v", get)
+	}
+	action := cluster.Folders[0].Requests[3]
+	if action.Path != "/clusters/{clusterId}" || len(action.Query) != 1 || !action.Query[0].Fixed || len(action.PathParams) != 1 {
+		t.Errorf("unexpected action request %+v", action)
+	}
+	if len(spec.Variables) != 2 || spec.Variables[0].Name != "hostname" || spec.Variables[1].Name != TokenVariable {
+		t.Errorf("expected hostname and token variables, got %+v", spec.Variables)
+	}
+}
+
+func TestPostman(t *testing.T) {
+	collection, environment := Postman(NewSpec(testSwagger()))
+
+	get := collection.Item[0].Item[0].Item[2].Request
+	if get.Method != "GET" || get.URL.Raw != "{{baseUrl}}/clusters/:clusterId" {
+		t.Errorf("expected the GET of a cluster without its shell, got %+v", get.URL)
+	}
+	action := collection.Item[0].Item[0].Item[3].Request
+	if action.URL.Raw != "{{baseUrl}}/clusters/:clusterId?action=generateKubeconfig" {
+		t.Errorf("unexpected action url %s", action.URL.Raw)
+	}
+	if len(action.URL.Variable) != 1 || action.URL.Variable[0].Key != "clusterId" {
+		t.Errorf("expected clusterId path variable, got %+v", action.URL.Variable)
+	}
+	if collection.Variable[0].Key != "baseUrl" || collection.Variable[0].Value != "https://{{hostname}}/v3" {
+		t.Errorf("expected baseUrl built from the server variables, got %+v", collection.Variable)
+	}
+	if collection.Auth.Bearer[0].Value != "{{token}}" {
+		t.Errorf("expected bearer auth from the token variable, got %+v", collection.Auth)
+	}
+	if len(environment.Values) != 2 || environment.Values[0].Value != "rancher.example.com" || environment.Values[1].Type != "secret" {
+		t.Errorf("unexpected environment %+v", environment.Values)
+	}
+}
+
+func TestInsomnia(t *testing.T) {
+	export := Insomnia(NewSpec(testSwagger()))
+
+	var request *InsomniaResource
+	env := export.Resources[len(export.Resources)-1]
+	for i, r := range export.Resources {
+		if r.Type == "request" && r.Name == "generateKubeconfig /clusters/{clusterId}?action=generateKubeconfig" {
+			request = &export.Resources[i]
+		}
+	}
+	if request == nil {
+		t.Fatalf("missing action request in %+v", export.Resources)
+	}
+	if request.URL != "https://{{ _.hostname }}/v3/clusters/{{ _.clusterId }}" {
+		t.Errorf("unexpected url %s", request.URL)
+	}
+	if len(request.Parameters) != 1 || request.Parameters[0].Name != "action" || request.Parameters[0].Disabled {
+		t.Errorf("expected enabled action parameter, got %+v", request.Parameters)
+	}
+	if env.Type != "environment" || env.Data["hostname"] != "rancher.example.com" {
+		t.Errorf("unexpected environment %+v", env)
+	}
+	if _, ok := env.Data["clusterId"]; !ok {
+		t.Errorf("expected clusterId in the environment, got %+v", env.Data)
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	swagger := testSwagger()
+	swagger.Paths["/v3-public/localProviders/{localProviderId}"] = openapi.PathItem{
+		Servers: []openapi.Server{{URL: "https://{hostname}"}},
+		Post: &openapi.Operation{
+			Tags:       []string{"localProvider"},
+			Parameters: []openapi.Parameter{{Name: "action", In: "query", Required: true}},
+			Security:   &[]map[string][]string{},
+			Extensions: openapi.Extensions{"x-rancher-actions": map[string]openapi.Action{"login": {}}},
+		},
+	}
+	spec := NewSpec(swagger)
+
+	collection, _ := Postman(spec)
+	var login *PostmanRequest
+	for _, folder := range collection.Item {
+		if folder.Name == "localProvider" {
+			login = folder.Item[0].Item[0].Request
+		}
+	}
+	if login == nil {
+		t.Fatalf("missing login request in %+v", collection.Item)
+	}
+	if login.URL.Raw != "https://{{hostname}}/v3-public/localProviders/:localProviderId?action=login" {
+		t.Errorf("expected login relative to the path's server, got %s", login.URL.Raw)
+	}
+	if login.Auth == nil || login.Auth.Type != "noauth" {
+		t.Errorf("expected login without auth, got %+v", login.Auth)
+	}
+
+	for _, r := range Insomnia(spec).Resources {
+		if r.Type == "request" && strings.HasPrefix(r.Name, "login ") {
+			if r.URL != "https://{{ _.hostname }}/v3-public/localProviders/{{ _.localProviderId }}" || r.Authentication["type"] != "none" {
+				t.Errorf("unexpected login request %+v", r)
+			}
+		}
+	}
+}
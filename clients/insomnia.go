@@ -0,0 +1,167 @@
+package clients
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+// InsomniaExport - Insomnia export format 4
+type InsomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	ExportDate   string             `json:"__export_date"`
+	ExportSource string             `json:"__export_source"`
+	Resources    []InsomniaResource `json:"resources"`
+}
+
+// InsomniaResource - a workspace, environment, request group or request
+type InsomniaResource struct {
+	ID             string                 `json:"_id"`
+	Type           string                 `json:"_type"`
+	ParentID       *string                `json:"parentId"`
+	Name           string                 `json:"name"`
+	Description    string                 `json:"description,omitempty"`
+	Data           map[string]string      `json:"data,omitempty"`
+	Method         string                 `json:"method,omitempty"`
+	URL            string                 `json:"url,omitempty"`
+	Body           *InsomniaBody          `json:"body,omitempty"`
+	Parameters     []InsomniaParameter    `json:"parameters,omitempty"`
+	Headers        []InsomniaParameter    `json:"headers,omitempty"`
+	Authentication map[string]interface{} `json:"authentication,omitempty"`
+}
+
+// InsomniaBody - a JSON request body
+type InsomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// InsomniaParameter - a query parameter or header
+type InsomniaParameter struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// Insomnia - convert spec to an Insomnia export.
+// Server variables, the token and path parameters are base environment variables.
+func Insomnia(spec *Spec) *InsomniaExport {
+	workspaceID := "wrk_rancher"
+	export := &InsomniaExport{
+		Type:         "export",
+		ExportFormat: 4,
+		ExportDate:   time.Now().UTC().Format(time.RFC3339),
+		ExportSource: "gen-api-docs",
+	}
+	export.Resources = append(export.Resources, InsomniaResource{
+		ID:          workspaceID,
+		Type:        "workspace",
+		Name:        spec.Name,
+		Description: spec.Description,
+	})
+
+	data := make(map[string]string)
+	for _, v := range spec.Variables {
+		data[v.Name] = v.Value
+	}
+	ids := 0
+	var walk func(parentID string, folders []*Folder)
+	walk = func(parentID string, folders []*Folder) {
+		for _, folder := range folders {
+			ids++
+			folderID := fmt.Sprintf("fld_%d", ids)
+			export.Resources = append(export.Resources, InsomniaResource{
+				ID:       folderID,
+				Type:     "request_group",
+				ParentID: stringPtr(parentID),
+				Name:     folder.Name,
+			})
+			walk(folderID, folder.Folders)
+			for _, r := range folder.Requests {
+				ids++
+				export.Resources = append(export.Resources, insomniaRequest(spec, fmt.Sprintf("req_%d", ids), folderID, r))
+				for _, p := range r.PathParams {
+					data[p.Name] = ""
+				}
+			}
+		}
+	}
+	walk(workspaceID, spec.Folders)
+
+	export.Resources = append(export.Resources, InsomniaResource{
+		ID:       "env_rancher",
+		Type:     "environment",
+		ParentID: stringPtr(workspaceID),
+		Name:     "Base Environment",
+		Data:     data,
+	})
+
+	return export
+}
+
+// WriteInsomnia - write <dir>/insomnia.json
+func WriteInsomnia(swagger *openapi.OpenAPI, dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, "insomnia.json"), Insomnia(NewSpec(swagger)))
+}
+
+func insomniaRequest(spec *Spec, id string, parentID string, r Request) InsomniaResource {
+	serverURL := spec.ServerURL
+	if r.ServerURL != "" {
+		serverURL = r.ServerURL
+	}
+	url := strings.TrimSuffix(substitute(serverURL, insomniaVariable), "/") + substitute(r.Path, insomniaVariable)
+
+	resource := InsomniaResource{
+		ID:          id,
+		Type:        "request",
+		ParentID:    stringPtr(parentID),
+		Name:        r.Name,
+		Description: r.Description,
+		Method:      r.Method,
+		URL:         url,
+		Headers: []InsomniaParameter{
+			{Name: "Accept", Value: "application/json"},
+		},
+		Authentication: map[string]interface{}{
+			"type":  "bearer",
+			"token": insomniaVariable(TokenVariable),
+		},
+	}
+	if r.NoAuth {
+		resource.Authentication = map[string]interface{}{"type": "none"}
+	}
+	for _, q := range r.Query {
+		resource.Parameters = append(resource.Parameters, InsomniaParameter{
+			Name:        q.Name,
+			Value:       q.Value,
+			Description: q.Description,
+			Disabled:    !q.Fixed,
+		})
+	}
+	if r.Body != "" {
+		resource.Headers = append(resource.Headers, InsomniaParameter{Name: "Content-Type", Value: "application/json"})
+		resource.Body = &InsomniaBody{
+			MimeType: "application/json",
+			Text:     r.Body,
+		}
+	}
+	return resource
+}
+
+func insomniaVariable(name string) string {
+	return fmt.Sprintf("{{ _.%s }}", name)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,238 @@
+package clients
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+const (
+	// PostmanSchema - Postman collection format version
+	PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	// postmanBaseURL - collection variable holding the server URL
+	postmanBaseURL = "baseUrl"
+)
+
+// PostmanCollection - https://schema.getpostman.com/json/collection/v2.1.0/docs/index.html
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Auth     *PostmanAuth      `json:"auth,omitempty"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+// PostmanInfo - collection name and format
+type PostmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem - a folder, with Item set, or a request
+type PostmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []PostmanItem   `json:"item,omitempty"`
+	Request     *PostmanRequest `json:"request,omitempty"`
+}
+
+// PostmanRequest - a request
+type PostmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []PostmanHeader `json:"header"`
+	URL         PostmanURL      `json:"url"`
+	Body        *PostmanBody    `json:"body,omitempty"`
+	Auth        *PostmanAuth    `json:"auth,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// PostmanHeader - a request header
+type PostmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// PostmanURL - a request URL, path variables as :name
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []PostmanVariable `json:"query,omitempty"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+// PostmanVariable - a collection, environment, path or query variable
+type PostmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// PostmanBody - a raw JSON request body
+type PostmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// PostmanAuth - bearer token auth, or noauth for unauthenticated requests
+type PostmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []PostmanVariable `json:"bearer,omitempty"`
+}
+
+// PostmanEnvironment - values for the collection variables
+type PostmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []PostmanVariable `json:"values"`
+}
+
+// Postman - convert spec to a Postman collection and environment
+func Postman(spec *Spec) (*PostmanCollection, *PostmanEnvironment) {
+	collection := &PostmanCollection{
+		Info: PostmanInfo{
+			Name:        spec.Name,
+			Description: spec.Description,
+			Schema:      PostmanSchema,
+		},
+		Item: postmanItems(spec, spec.Folders),
+		Auth: &PostmanAuth{
+			Type: "bearer",
+			Bearer: []PostmanVariable{
+				{Key: "token", Value: postmanVariable(TokenVariable), Type: "string"},
+			},
+		},
+	}
+
+	// server URL built from the server variables, e.g. https://{{hostname}}/v3
+	collection.Variable = append(collection.Variable, PostmanVariable{
+		Key:   postmanBaseURL,
+		Value: strings.TrimSuffix(substitute(spec.ServerURL, postmanVariable), "/"),
+	})
+
+	environment := &PostmanEnvironment{
+		Name: spec.Name,
+	}
+	enabled := true
+	for _, v := range spec.Variables {
+		collection.Variable = append(collection.Variable, PostmanVariable{
+			Key:         v.Name,
+			Value:       v.Value,
+			Description: v.Description,
+		})
+		variable := PostmanVariable{
+			Key:     v.Name,
+			Value:   v.Value,
+			Enabled: &enabled,
+		}
+		if v.Name == TokenVariable {
+			variable.Type = "secret"
+		}
+		environment.Values = append(environment.Values, variable)
+	}
+
+	return collection, environment
+}
+
+// WritePostman - write <dir>/postman_collection.json and postman_environment.json
+func WritePostman(swagger *openapi.OpenAPI, dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	collection, environment := Postman(NewSpec(swagger))
+	err = writeJSON(filepath.Join(dir, "postman_collection.json"), collection)
+	if err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, "postman_environment.json"), environment)
+}
+
+func postmanItems(spec *Spec, folders []*Folder) []PostmanItem {
+	items := make([]PostmanItem, 0, len(folders))
+	for _, folder := range folders {
+		item := PostmanItem{
+			Name: folder.Name,
+			Item: postmanItems(spec, folder.Folders),
+		}
+		for _, r := range folder.Requests {
+			item.Item = append(item.Item, postmanRequest(spec, r))
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func postmanRequest(spec *Spec, r Request) PostmanItem {
+	base := postmanVariable(postmanBaseURL)
+	if r.ServerURL != "" {
+		base = strings.TrimSuffix(substitute(r.ServerURL, postmanVariable), "/")
+	}
+	path := substitute(r.Path, func(name string) string {
+		return ":" + name
+	})
+
+	url := PostmanURL{
+		Host: []string{base},
+		Path: strings.Split(strings.TrimPrefix(path, "/"), "/"),
+	}
+	query := make([]string, 0)
+	for _, q := range r.Query {
+		url.Query = append(url.Query, PostmanVariable{
+			Key:         q.Name,
+			Value:       q.Value,
+			Description: q.Description,
+			Disabled:    !q.Fixed,
+		})
+		if q.Fixed {
+			query = append(query, fmt.Sprintf("%s=%s", q.Name, q.Value))
+		}
+	}
+	for _, p := range r.PathParams {
+		url.Variable = append(url.Variable, PostmanVariable{
+			Key:         p.Name,
+			Description: p.Description,
+		})
+	}
+	url.Raw = base + path
+	if len(query) > 0 {
+		url.Raw += "?" + strings.Join(query, "&")
+	}
+
+	request := &PostmanRequest{
+		Method: r.Method,
+		Header: []PostmanHeader{
+			{Key: "Accept", Value: "application/json"},
+		},
+		URL:         url,
+		Description: r.Description,
+	}
+	if r.NoAuth {
+		request.Auth = &PostmanAuth{Type: "noauth"}
+	}
+	if r.Body != "" {
+		request.Header = append(request.Header, PostmanHeader{Key: "Content-Type", Value: "application/json"})
+		request.Body = &PostmanBody{
+			Mode: "raw",
+			Raw:  r.Body,
+			Options: map[string]interface{}{
+				"raw": map[string]string{"language": "json"},
+			},
+		}
+	}
+
+	return PostmanItem{
+		Name:    r.Name,
+		Request: request,
+	}
+}
+
+func postmanVariable(name string) string {
+	return fmt.Sprintf("{{%s}}", name)
+}
@@ -0,0 +1,355 @@
+// Package fake serves a norman style Rancher API from a declarative fixture
+// so the generator can be exercised without a Rancher server.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	norman "github.com/rancher/norman/types"
+)
+
+// Fixture - documents served by the fake Rancher API.
+// Links, actions and createTypes starting with `/` are relative to the server
+// and are rewritten to absolute URLs when served.
+type Fixture struct {
+	// Schemas by API root, e.g. /v3
+	Schemas map[string][]norman.Schema `json:"schemas"`
+	// Collections by path, e.g. /v3/clusters
+	Collections map[string]Collection `json:"collections"`
+	// Files - documents that aren't JSON by path, e.g. /v3/clusters/c-1/yaml
+	Files map[string]File `json:"files,omitempty"`
+}
+
+// File - a document served as is with its own Content-Type
+type File struct {
+	ContentType string `json:"contentType"`
+	Body        string `json:"body"`
+}
+
+// Collection - a collection and the resources in it.
+// Resources with a self link are also served on their own.
+type Collection struct {
+	ResourceType string                   `json:"resourceType"`
+	CreateTypes  map[string]string        `json:"createTypes,omitempty"`
+	Data         []map[string]interface{} `json:"data"`
+}
+
+// Server - httptest server for a Fixture
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	fixture  *Fixture
+	pageSize int
+	requests []string
+}
+
+// LoadFixture - read a JSON fixture file
+func LoadFixture(file string) (*Fixture, error) {
+	fixture := &Fixture{}
+	jsonFixture, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(jsonFixture, fixture)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse fixture %s - %v", file, err)
+	}
+	return fixture, nil
+}
+
+// NewServer - start serving fixture, Close the server when done
+func NewServer(fixture *Fixture) *Server {
+	s := &Server{
+		fixture: fixture,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// SetSchemaPageSize - split the schemas collection into pages of size schemas
+// linked by pagination.next, like norman does with ?limit=. 0 serves one page.
+func (s *Server) SetSchemaPageSize(size int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.pageSize = size
+}
+
+// Requests - the path and query of every request served so far
+func (s *Server) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	requests := make([]string, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// SetSchema - add or replace a schema under root, as if Rancher was upgraded
+func (s *Server) SetSchema(root string, schema norman.Schema) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	schemas := s.fixture.Schemas[root]
+	for i := range schemas {
+		if schemas[i].ID == schema.ID {
+			schemas[i] = schema
+			return
+		}
+	}
+	s.fixture.Schemas[root] = append(schemas, schema)
+}
+
+// RemoveSchema - remove a schema from root
+func (s *Server) RemoveSchema(root string, id string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	schemas := s.fixture.Schemas[root]
+	for i := range schemas {
+		if schemas[i].ID == id {
+			s.fixture.Schemas[root] = append(schemas[:i:i], schemas[i+1:]...)
+			return
+		}
+	}
+}
+
+// Schema - the schema id under root. Its maps are shared with the fixture, copy them before changing it.
+func (s *Server) Schema(root string, id string) (norman.Schema, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, schema := range s.fixture.Schemas[root] {
+		if schema.ID == id {
+			return schema, true
+		}
+	}
+	return norman.Schema{}, false
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requests = append(s.requests, r.URL.RequestURI())
+	if file, ok := s.fixture.Files[r.URL.Path]; ok {
+		w.Header().Set("Content-Type", file.ContentType)
+		w.Write([]byte(file.Body))
+		return
+	}
+	doc, ok := s.document(r.URL.Path, r.URL.Query())
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"type":    "error",
+			"status":  http.StatusNotFound,
+			"code":    "NotFound",
+			"message": fmt.Sprintf("%s not found", r.URL.Path),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.absolute(doc))
+}
+
+// document - the document for a path, built from the fixture
+func (s *Server) document(path string, query url.Values) (interface{}, bool) {
+	path = strings.TrimSuffix(path, "/")
+
+	for root, schemas := range s.fixture.Schemas {
+		switch {
+		case path == root:
+			return s.apiRoot(root), true
+		case path == root+"/schemas":
+			return s.schemaCollection(root, schemas, query.Get("marker")), true
+		case strings.HasPrefix(path, root+"/schemas/"):
+			id := strings.TrimPrefix(path, root+"/schemas/")
+			for _, schema := range schemas {
+				if schema.ID == id {
+					return toMap(withVersion(root, schema)), true
+				}
+			}
+			return nil, false
+		}
+	}
+
+	for colPath, collection := range s.fixture.Collections {
+		if path == colPath {
+			return collectionDocument(colPath, collection), true
+		}
+		for _, resource := range collection.Data {
+			if links, ok := resource["links"].(map[string]interface{}); ok && links["self"] == path {
+				return resource, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// apiRoot - links to every collection directly below root
+func (s *Server) apiRoot(root string) map[string]interface{} {
+	links := map[string]interface{}{
+		"self": root,
+	}
+	for colPath := range s.fixture.Collections {
+		name := strings.TrimPrefix(colPath, root+"/")
+		if strings.HasPrefix(colPath, root+"/") && !strings.Contains(name, "/") {
+			links[name] = colPath
+		}
+	}
+	return map[string]interface{}{
+		"id":    strings.TrimPrefix(root, "/"),
+		"type":  "apiRoot",
+		"links": links,
+	}
+}
+
+func collectionDocument(path string, collection Collection) map[string]interface{} {
+	createTypes := collection.CreateTypes
+	if createTypes == nil {
+		createTypes = map[string]string{
+			collection.ResourceType: path,
+		}
+	}
+	data := collection.Data
+	if data == nil {
+		data = make([]map[string]interface{}, 0)
+	}
+	return map[string]interface{}{
+		"type":         "collection",
+		"resourceType": collection.ResourceType,
+		"links": map[string]interface{}{
+			"self": path,
+		},
+		"createTypes": toMap(createTypes),
+		"actions":     map[string]interface{}{},
+		"data":        data,
+	}
+}
+
+// schemaCollection - the page of schemas starting after marker
+func (s *Server) schemaCollection(root string, schemas []norman.Schema, marker string) map[string]interface{} {
+	sorted := make([]norman.Schema, len(schemas))
+	copy(sorted, schemas)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	start := 0
+	for marker != "" && start < len(sorted) && sorted[start].ID <= marker {
+		start++
+	}
+	end := len(sorted)
+	if s.pageSize > 0 && start+s.pageSize < end {
+		end = start + s.pageSize
+	}
+
+	data := make([]interface{}, 0, end-start)
+	for _, schema := range sorted[start:end] {
+		data = append(data, toMap(withVersion(root, schema)))
+	}
+	doc := map[string]interface{}{
+		"type":         "collection",
+		"resourceType": "schema",
+		"links": map[string]interface{}{
+			"self": root + "/schemas",
+		},
+		"data": data,
+	}
+	if s.pageSize > 0 {
+		pagination := map[string]interface{}{
+			"limit":   s.pageSize,
+			"total":   len(sorted),
+			"partial": end < len(sorted),
+		}
+		if end < len(sorted) {
+			pagination["next"] = fmt.Sprintf("%s%s/schemas?limit=%d&marker=%s", s.URL, root, s.pageSize, sorted[end-1].ID)
+		}
+		doc["pagination"] = pagination
+	}
+	return doc
+}
+
+// withVersion - fill in the fields norman sets on every schema it serves
+func withVersion(root string, schema norman.Schema) norman.Schema {
+	if schema.Type == "" {
+		schema.Type = "schema"
+	}
+	if schema.Version.Path == "" {
+		schema.Version.Path = root
+	}
+	links := map[string]string{
+		"self": fmt.Sprintf("%s/schemas/%s", root, schema.ID),
+	}
+	for k, v := range schema.Links {
+		links[k] = v
+	}
+	schema.Links = links
+	return schema
+}
+
+// absolute - copy of doc with relative links rewritten onto the server URL
+func (s *Server) absolute(doc interface{}) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if key == "links" || key == "actions" || key == "createTypes" {
+				if links, ok := value.(map[string]interface{}); ok {
+					out[key] = s.absoluteLinks(links)
+					continue
+				}
+			}
+			out[key] = s.absolute(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = s.absolute(value)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = s.absolute(value)
+		}
+		return out
+	}
+	return doc
+}
+
+func (s *Server) absoluteLinks(links map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(links))
+	for name, link := range links {
+		if l, ok := link.(string); ok && strings.HasPrefix(l, "/") {
+			out[name] = s.URL + l
+			continue
+		}
+		out[name] = link
+	}
+	return out
+}
+
+// toMap - round trip through JSON so typed values can be rewritten by absolute
+func toMap(v interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	data, err := json.Marshal(v)
+	if err != nil {
+		return out
+	}
+	json.Unmarshal(data, &out)
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, doc interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(doc)
+}
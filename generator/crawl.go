@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"fmt"
+	neturl "net/url"
+	"path"
+	"strings"
+)
+
+// crawlTarget - a collection link waiting to be parsed
+type crawlTarget struct {
+	Col   string
+	Link  string
+	Base  string
+	Depth int
+}
+
+// Edge - a link followed (or pruned) while crawling
+type Edge struct {
+	From   string `json:"from"`
+	Name   string `json:"name"`
+	To     string `json:"to"`
+	Depth  int    `json:"depth"`
+	Pruned string `json:"pruned,omitempty"`
+}
+
+// crawlGraph - tracks every link seen while crawling so cycles and overly deep
+// link chains are pruned instead of being walked again.
+// Targets are handed out breadth first, so a collection is always documented
+// under the shortest path it can be reached by.
+type crawlGraph struct {
+	maxDepth int
+	visited  map[string]string
+	queue    []crawlTarget
+	edges    []Edge
+}
+
+func newCrawlGraph(maxDepth int) *crawlGraph {
+	return &crawlGraph{
+		maxDepth: maxDepth,
+		visited:  make(map[string]string),
+	}
+}
+
+// push - record the edge from -> link and queue it if it should be followed.
+// Root collections are depth 0, a negative maxDepth does not limit the depth.
+func (g *crawlGraph) push(from string, col string, link string, base string, depth int) bool {
+	to := normalizeLink(link)
+	edge := Edge{
+		From:  from,
+		Name:  col,
+		To:    to,
+		Depth: depth,
+	}
+
+	if g.maxDepth >= 0 && depth > g.maxDepth {
+		edge.Pruned = fmt.Sprintf("max depth %d exceeded", g.maxDepth)
+	} else if seen, ok := g.visited[to]; ok {
+		edge.Pruned = fmt.Sprintf("already visited as %s", seen)
+	}
+	g.edges = append(g.edges, edge)
+
+	if edge.Pruned != "" {
+		return false
+	}
+
+	g.visited[to] = fmt.Sprintf("%s%s", base, col)
+	g.queue = append(g.queue, crawlTarget{
+		Col:   col,
+		Link:  link,
+		Base:  base,
+		Depth: depth,
+	})
+	return true
+}
+
+// see - mark a link as visited without following it.
+// Used for resources so links from sub collections back to their parent are pruned.
+func (g *crawlGraph) see(link string, path string) {
+	if link == "" {
+		return
+	}
+	to := normalizeLink(link)
+	if _, ok := g.visited[to]; !ok {
+		g.visited[to] = path
+	}
+}
+
+// next - pop the next target off the queue
+func (g *crawlGraph) next() (crawlTarget, bool) {
+	if len(g.queue) == 0 {
+		return crawlTarget{}, false
+	}
+	target := g.queue[0]
+	g.queue = g.queue[1:]
+	return target, true
+}
+
+// pruned - edges that were not followed
+func (g *crawlGraph) pruned() []Edge {
+	pruned := make([]Edge, 0)
+	for _, edge := range g.edges {
+		if edge.Pruned != "" {
+			pruned = append(pruned, edge)
+		}
+	}
+	return pruned
+}
+
+// normalizeLink - reduce a link to the resource it points at.
+// Scheme and host are lower cased, default ports, fragments and trailing
+// slashes are dropped. Query parameters are kept, sorted, since a filtered
+// view of a collection (?clusterId=...) is documented as a nested path.
+func normalizeLink(link string) string {
+	u, err := neturl.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if (u.Scheme == "https" && strings.HasSuffix(host, ":443")) || (u.Scheme == "http" && strings.HasSuffix(host, ":80")) {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+	u.Host = host
+
+	if u.Path != "" {
+		u.Path = path.Clean(u.Path)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	u.User = nil
+
+	return u.String()
+}
@@ -0,0 +1,17 @@
+package generator
+
+import "testing"
+
+func TestNormalizeLink(t *testing.T) {
+	for link, expected := range map[string]string{
+		"https://Rancher.Example.com:443/v3/clusters/":        "https://rancher.example.com/v3/clusters",
+		"https://rancher.example.com/v3/projects?clusterId=c": "https://rancher.example.com/v3/projects?clusterId=c",
+		"https://rancher.example.com/v3/nodes?b=2&a=1":        "https://rancher.example.com/v3/nodes?a=1&b=2",
+		"http://rancher.example.com:80/v3//nodes#top":         "http://rancher.example.com/v3/nodes",
+		"https://rancher.example.com:8443/v3/nodes":           "https://rancher.example.com:8443/v3/nodes",
+	} {
+		if actual := normalizeLink(link); actual != expected {
+			t.Errorf("%s: expected %s, got %s", link, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	norman "github.com/rancher/norman/types"
+)
+
+// DirSource - a directory of JSON documents saved from the API, one file per
+// link: the path of the link with a .json extension, e.g.
+//
+//	v3.json                   the API root
+//	v3/clusters.json          a collection
+//	v3/schemas.json           the schemas collection
+//	v3/schemas/cluster.json   a schema, optional when it is in schemas.json
+//
+// A link with a query keeps it in the file name after an underscore, sorted
+// and escaped, v3/projects_clusterId=c-1.json or v3/pods_projectId=c-1%3Ap-1.json
+//
+// Documents that aren't JSON, like exports, keep their own extension instead,
+// v3/clusters/c-1/yaml.yaml or v3/templateVersions/x/icon.png
+type DirSource struct {
+	dir string
+}
+
+// NewDirSource - read the documents saved in dir
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{dir: dir}
+}
+
+// Collections - links in the saved API root
+func (s *DirSource) Collections(link string) (map[string]string, error) {
+	return getCollections(s.Get, link)
+}
+
+// Collection - a saved collection
+func (s *DirSource) Collection(link string) (*Collection, error) {
+	return getCollection(s.Get, link)
+}
+
+// Schema - a saved schema, or the schema with the same ID in the saved schemas collection
+func (s *DirSource) Schema(link string) (norman.Schema, error) {
+	schema, err := getSchema(s.Get, link)
+	if err == nil || !os.IsNotExist(err) {
+		return schema, err
+	}
+
+	root, id := path.Split(strings.TrimSuffix(link, "/"))
+	schemas, listErr := getSchemas(s.Get, strings.TrimSuffix(strings.TrimSuffix(root, "/"), "/schemas"))
+	if listErr != nil {
+		return schema, err
+	}
+	for _, schema := range schemas {
+		if schema.ID == id {
+			return schema, nil
+		}
+	}
+	return schema, err
+}
+
+// Schemas - the saved schemas collection
+func (s *DirSource) Schemas(link string) ([]norman.Schema, error) {
+	return getSchemas(s.Get, link)
+}
+
+// DynamicSchemas - the saved dynamicSchemas collection
+func (s *DirSource) DynamicSchemas(link string) ([]DynamicSchema, error) {
+	return getDynamicSchemas(s.Get, link)
+}
+
+// Get - the saved JSON document for link
+func (s *DirSource) Get(link string) ([]byte, error) {
+	file, err := linkFile(link)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.Join(s.dir, file))
+}
+
+// ContentType - application/json for a saved JSON document, otherwise the
+// media type of the extension of the document saved for link
+func (s *DirSource) ContentType(link string) (string, error) {
+	file, err := linkFile(link)
+	if err != nil {
+		return "", err
+	}
+	file = filepath.Join(s.dir, file)
+	if _, err := os.Stat(file); err == nil {
+		return "application/json", nil
+	}
+
+	matches, err := filepath.Glob(strings.TrimSuffix(file, ".json") + ".*")
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("Nothing saved for %s", link)
+	}
+	ext := filepath.Ext(matches[0])
+	if mediaType, ok := mediaTypes[ext]; ok {
+		return mediaType, nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(mime.TypeByExtension(ext)); err == nil {
+		return mediaType, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// mediaTypes - extensions the mime package doesn't know everywhere
+var mediaTypes = map[string]string{
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".md":   "text/markdown",
+}
+
+// linkFile - relative file a link is saved in
+func linkFile(link string) (string, error) {
+	u, err := neturl.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	p := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	if p == "" {
+		return "", fmt.Errorf("%s has no path", link)
+	}
+	// ? is not allowed in file names on Windows or in module zips
+	if u.RawQuery != "" {
+		p = fmt.Sprintf("%s_%s", p, u.Query().Encode())
+	}
+	return filepath.FromSlash(p) + ".json", nil
+}
+
+// BundleSource - a single dump of the schemas collection, e.g. from a support bundle.
+// Without the collections every schema with collection methods is documented
+// as a root collection named after its plural name.
+type BundleSource struct {
+	schemas []norman.Schema
+}
+
+// NewBundleSource - read a dump of the schemas collection
+func NewBundleSource(file string) (*BundleSource, error) {
+	dump, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	list := &schemaList{}
+	err = json.Unmarshal(dump, list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse schemas %s - %v", file, err)
+	}
+	return &BundleSource{schemas: list.Data}, nil
+}
+
+// Collections - a link for every schema with collection methods
+func (s *BundleSource) Collections(link string) (map[string]string, error) {
+	links := make(map[string]string)
+	for _, schema := range s.schemas {
+		if colLink, ok := s.collectionLink(link, schema); ok {
+			links[schema.PluralName] = colLink
+		}
+	}
+	return links, nil
+}
+
+// Collection - an empty collection of the schema the link belongs to
+func (s *BundleSource) Collection(link string) (*Collection, error) {
+	i := strings.LastIndex(link, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("%s is not in the schemas bundle", link)
+	}
+	root := link[:i]
+	for _, schema := range s.schemas {
+		if colLink, ok := s.collectionLink(root, schema); ok && colLink == link {
+			return &Collection{
+				Collection: &norman.Collection{
+					Type:         "collection",
+					ResourceType: schema.ID,
+					Links:        map[string]string{"self": link},
+					CreateTypes:  map[string]string{schema.ID: link},
+				},
+				Data: make([]norman.Resource, 0),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not in the schemas bundle", link)
+}
+
+// Schema - the schema with the ID the link ends in
+func (s *BundleSource) Schema(link string) (norman.Schema, error) {
+	id := path.Base(link)
+	for _, schema := range s.schemas {
+		if schema.ID == id {
+			return schema, nil
+		}
+	}
+	return norman.Schema{}, fmt.Errorf("Schema %s is not in the schemas bundle", id)
+}
+
+// Schemas - the schemas in the bundle served from the API root link, the ones
+// of its version, e.g. /v3/cluster for /v3/cluster/c-1, and those without a version
+func (s *BundleSource) Schemas(link string) ([]norman.Schema, error) {
+	u, err := neturl.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	version := s.versionPath(u.Path)
+	schemas := make([]norman.Schema, 0, len(s.schemas))
+	for _, schema := range s.schemas {
+		if schema.Version.Path == "" || path.Clean(schema.Version.Path) == version {
+			schemas = append(schemas, schema)
+		}
+	}
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("%s/schemas is not in the schemas bundle", link)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].ID < schemas[j].ID
+	})
+	return schemas, nil
+}
+
+// DynamicSchemas - dynamic schemas of active drivers are in the schemas
+// collection the bundle was dumped from
+func (s *BundleSource) DynamicSchemas(link string) ([]DynamicSchema, error) {
+	return nil, fmt.Errorf("%s is not in the schemas bundle", link)
+}
+
+// ContentType - the bundle only has schemas, there is nothing to probe
+func (s *BundleSource) ContentType(link string) (string, error) {
+	return "", fmt.Errorf("%s is not in the schemas bundle", link)
+}
+
+// versionPath - the longest version path of the schemas in the bundle that
+// root is, or is under, e.g. /v3/cluster for /v3/cluster/c-1
+func (s *BundleSource) versionPath(root string) string {
+	root = path.Clean("/" + root)
+	version := ""
+	for _, schema := range s.schemas {
+		if schema.Version.Path == "" {
+			continue
+		}
+		p := path.Clean(schema.Version.Path)
+		if (root == p || strings.HasPrefix(root, p+"/")) && len(p) > len(version) {
+			version = p
+		}
+	}
+	return version
+}
+
+// collectionLink - where the collection of schema would be under the API root.
+// Only schemas served from the root itself have a collection there.
+func (s *BundleSource) collectionLink(root string, schema norman.Schema) (string, bool) {
+	if len(schema.CollectionMethods) == 0 || schema.PluralName == "" {
+		return "", false
+	}
+	u, err := neturl.Parse(root)
+	if err != nil {
+		return "", false
+	}
+	if schema.Version.Path != "" && path.Clean(schema.Version.Path) != path.Clean(u.Path) {
+		return "", false
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(root, "/"), schema.PluralName), true
+}
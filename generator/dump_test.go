@@ -0,0 +1,288 @@
+package generator
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// recorder - saves every response in dir the way DirSource reads them
+type recorder struct {
+	t   *testing.T
+	dir string
+}
+
+func (r recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	file, err := linkFile(req.URL.String())
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); !isJSON(mediaType) {
+		file = strings.TrimSuffix(file, ".json") + extension(mediaType)
+	}
+	file = filepath.Join(r.dir, file)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, body, 0644); err != nil {
+		r.t.Fatal(err)
+	}
+	return resp, nil
+}
+
+// extension - what DirSource reads as mediaType
+func extension(mediaType string) string {
+	for ext, t := range mediaTypes {
+		if t == mediaType && ext != ".yml" {
+			return ext
+		}
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+func paths(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
+
+func TestDirSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen-api-docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv := newFakeRancher(t)
+	url := srv.URL + "/v3"
+	live := New(Options{
+		URL:      url,
+		Client:   &http.Client{Transport: recorder{t: t, dir: dir}},
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+	})
+	liveSwagger, _, err := live.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := live.Schemas(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	// Schemas missing on their own are found in schemas.json
+	if err := os.RemoveAll(filepath.Join(dir, "v3", "schemas")); err != nil {
+		t.Fatal(err)
+	}
+
+	swagger, report, err := New(Options{
+		URL:      url,
+		Source:   NewDirSource(dir),
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Failed) != 0 {
+		t.Errorf("expected no failures, got %+v", report.Failed)
+	}
+
+	expected, actual := make(map[string]bool), make(map[string]bool)
+	for path := range liveSwagger.Paths {
+		expected[path] = true
+	}
+	for path := range swagger.Paths {
+		actual[path] = true
+	}
+	if paths(expected) != paths(actual) {
+		t.Errorf("expected paths:\n%s\ngot:\n%s", paths(expected), paths(actual))
+	}
+	if len(swagger.Components.Schemas) != len(liveSwagger.Components.Schemas) {
+		t.Errorf("expected %d schemas, got %d", len(liveSwagger.Components.Schemas), len(swagger.Components.Schemas))
+	}
+}
+
+func TestBundleSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen-api-docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv := newFakeRancher(t)
+	url := srv.URL + "/v3"
+	resp, err := http.Get(url + "/schemas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	srv.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(dir, "schemas.json")
+	if err := ioutil.WriteFile(bundle, dump, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewBundleSource(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger, report, err := New(Options{
+		URL:      url,
+		Source:   source,
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPaths(t, swagger, []string{
+		"/clusters",
+		"/clusters/{clusterId}",
+		"/nodes",
+		"/projects/{projectId}",
+	}, []string{
+		"/clusters/{clusterId}/nodes",
+	})
+	if post := swagger.Paths["/clusters/{clusterId}"].Post; post == nil || post.Actions()["generateKubeconfig"].Output == nil {
+		t.Errorf("expected the generateKubeconfig action from the bundle, got %+v", post)
+	}
+	if _, ok := swagger.Components.Schemas["rancherKubernetesEngineConfig"]; !ok {
+		t.Error("expected referenced schemas to be resolved from the bundle")
+	}
+	if len(report.Failed) != 0 {
+		t.Errorf("expected no failures, got %+v", report.Failed)
+	}
+}
+
+func TestBundleSourceRoots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen-api-docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundle := filepath.Join(dir, "schemas.json")
+	dump := `{"data": [
+		{"id": "cluster", "pluralName": "clusters", "collectionMethods": ["GET"]},
+		{"id": "namespace", "pluralName": "namespaces", "version": {"path": "/v3/cluster"}, "collectionMethods": ["GET"]}
+	]}`
+	if err := ioutil.WriteFile(bundle, []byte(dump), 0644); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewBundleSource(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := source.Collection("clusters"); err == nil {
+		t.Error("expected an error for a collection link without a root")
+	}
+	for root, expected := range map[string][]string{
+		"https://rancher.example.com/v3":             {"cluster"},
+		"https://rancher.example.com/v3/cluster/c-1": {"cluster", "namespace"},
+	} {
+		schemas, err := source.Schemas(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, 0, len(schemas))
+		for _, schema := range schemas {
+			ids = append(ids, schema.ID)
+		}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Errorf("expected %v in %s/schemas, got %v", expected, root, ids)
+		}
+	}
+}
+
+func TestDirSourceContentType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen-api-docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for file, body := range map[string]string{
+		"v3/clusters.json":     "{}",
+		"v3/clusters/yaml.yml": "kind: Cluster",
+		"v3/icon.png":          "PNG",
+		"v3/readme.md":         "# readme",
+	} {
+		file = filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := NewDirSource(dir)
+	for link, expected := range map[string]string{
+		"https://rancher.example.com/v3/clusters":      "application/json",
+		"https://rancher.example.com/v3/clusters/yaml": "application/yaml",
+		"https://rancher.example.com/v3/icon":          "image/png",
+		"https://rancher.example.com/v3/readme":        "text/markdown",
+	} {
+		mediaType, err := source.ContentType(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mediaType != expected {
+			t.Errorf("%s: expected %s, got %s", link, expected, mediaType)
+		}
+	}
+	if _, err := source.ContentType("https://rancher.example.com/v3/missing"); err == nil {
+		t.Error("expected an error for a link that wasn't saved")
+	}
+}
+
+func TestLinkFile(t *testing.T) {
+	for link, expected := range map[string]string{
+		"https://rancher.example.com/v3":                            "v3.json",
+		"https://rancher.example.com/v3/clusters/":                  "v3/clusters.json",
+		"https://rancher.example.com/v3/projects?clusterId=c-1":     "v3/projects_clusterId=c-1.json",
+		"https://rancher.example.com/v3/pods?projectId=c-1:p-1&a=b": "v3/pods_a=b&projectId=c-1%3Ap-1.json",
+	} {
+		file, err := linkFile(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if file != filepath.FromSlash(expected) {
+			t.Errorf("%s: expected %s, got %s", link, expected, file)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// DynamicSchema - a schema Rancher generates for an active driver, e.g.
+// amazonec2Config for the amazonec2 node driver, listed in the dynamicSchemas
+// collection of the management API. SchemaName is the ID it is referenced by,
+// the ID of the resource is its lower case Kubernetes name.
+type DynamicSchema struct {
+	norman.Schema
+	SchemaName      string           `json:"schemaName,omitempty"`
+	OwnerReferences []OwnerReference `json:"ownerReferences,omitempty"`
+}
+
+// OwnerReference - the driver that owns a dynamic schema
+type OwnerReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// dynamicSchemaList - a page of the dynamicSchemas collection
+type dynamicSchemaList struct {
+	Data       []DynamicSchema    `json:"data"`
+	Pagination *norman.Pagination `json:"pagination,omitempty"`
+}
+
+// driverKinds - owners of dynamic schemas that are drivers
+var driverKinds = map[string]bool{
+	"NodeDriver":      true,
+	"KontainerDriver": true,
+}
+
+// SchemaID - the schema ID the dynamic schema is referenced by
+func (s DynamicSchema) SchemaID() string {
+	if s.SchemaName != "" {
+		return s.SchemaName
+	}
+	return s.Schema.ID
+}
+
+// Driver - the driver providing the schema. Owned by a node or cluster
+// driver, otherwise named after the schema, amazonec2Config -> amazonec2.
+func (s DynamicSchema) Driver() openapi.Driver {
+	for _, owner := range s.OwnerReferences {
+		if driverKinds[owner.Kind] {
+			return openapi.Driver{Name: owner.Name, Kind: owner.Kind}
+		}
+	}
+	name := strings.TrimSuffix(s.SchemaID(), "Config")
+	name = strings.TrimSuffix(name, "Engine")
+	return openapi.Driver{Name: name}
+}
+
+// getDynamicSchemas - the dynamicSchemas collection at link, following the next page links
+func getDynamicSchemas(get getter, link string) ([]DynamicSchema, error) {
+	schemas := make([]DynamicSchema, 0)
+	seen := make(map[string]bool)
+	for next := link; next != ""; {
+		if seen[next] {
+			return nil, fmt.Errorf("Pagination of %s loops back to %s", link, next)
+		}
+		seen[next] = true
+
+		response, err := get(next)
+		if err != nil {
+			return nil, err
+		}
+		list := &dynamicSchemaList{}
+		err = json.Unmarshal(response, list)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, list.Data...)
+
+		next = ""
+		if list.Pagination != nil {
+			next = list.Pagination.Next
+		}
+	}
+	return schemas, nil
+}
+
+// loadDynamicSchemas - add the dynamic schemas of root to its index and
+// remember the driver of each. Schemas already served by <root>/schemas
+// keep that version, it has the fields of the driver merged in.
+// Embedded schemas only add fields to their embedType and aren't indexed.
+func (g *Generator) loadDynamicSchemas(root string, index map[string]norman.Schema) {
+	link, ok := g.dynamicLinks[root]
+	if !ok {
+		return
+	}
+	schemas, err := g.source.DynamicSchemas(link)
+	if err != nil {
+		log.Warnf("Failed to load dynamic schemas %s - %v", link, err)
+		return
+	}
+
+	drivers := make(map[string]openapi.Driver)
+	for _, dynamic := range schemas {
+		if dynamic.Embed {
+			continue
+		}
+		id := dynamic.SchemaID()
+		drivers[id] = dynamic.Driver()
+		if _, ok := index[id]; ok {
+			continue
+		}
+		schema := dynamic.Schema
+		schema.ID = id
+		index[id] = schema
+	}
+	g.drivers[root] = drivers
+	log.Debugf("Loaded %d dynamic schemas from %s", len(drivers), link)
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"testing"
+
+	norman "github.com/rancher/norman/types"
+)
+
+func TestGenerateDynamicSchemas(t *testing.T) {
+	swagger, report := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	for name, expected := range map[string]string{
+		"amazonec2Config":    "amazonec2 NodeDriver",
+		"digitaloceanConfig": "digitalocean ",
+	} {
+		schema, ok := swagger.Components.Schemas[name]
+		if !ok {
+			t.Errorf("expected the dynamic schema %s", name)
+			continue
+		}
+		if _, ok := schema.Properties["region"]; !ok {
+			t.Errorf("expected %s to be translated, got %+v", name, schema)
+		}
+		if schema.Driver() == nil || schema.Driver().Name+" "+schema.Driver().Kind != expected {
+			t.Errorf("expected %s to be provided by %s, got %+v", name, expected, schema.Driver())
+		}
+	}
+	if _, ok := swagger.Components.Schemas["nodeTemplateConfig"]; ok {
+		t.Error("expected the embedded nodeTemplateConfig to be left out")
+	}
+	if driver := swagger.Components.Schemas["nodeTemplate"].Driver(); driver != nil {
+		t.Errorf("expected no driver on nodeTemplate, got %+v", driver)
+	}
+	if len(report.Missing) != 0 {
+		t.Errorf("expected no missing schemas, got %+v", report.Missing)
+	}
+	assertPaths(t, swagger, []string{"/dynamicSchemas"}, nil)
+}
+
+func TestGenerateDynamicSchemasServedAsSchemas(t *testing.T) {
+	srv := newFakeRancher(t)
+	defer srv.Close()
+	srv.SetSchema("/v3", norman.Schema{
+		ID: "amazonec2Config",
+		ResourceFields: map[string]norman.Field{
+			"region": {Type: "string", Create: true, Update: true},
+			"zone":   {Type: "string", Create: true, Update: true},
+		},
+	})
+
+	swagger, _, err := New(Options{
+		URL:      srv.URL + "/v3",
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := swagger.Components.Schemas["amazonec2Config"]
+	if _, ok := schema.Properties["zone"]; !ok {
+		t.Errorf("expected the schema served by /v3/schemas, got %+v", schema)
+	}
+	if schema.Driver() == nil || schema.Driver().Name != "amazonec2" {
+		t.Errorf("expected amazonec2Config to still be marked, got %+v", schema.Driver())
+	}
+}
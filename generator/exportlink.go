@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	log "github.com/sirupsen/logrus"
+)
+
+// isJSON - application/json, or a JSON based type like application/vnd.api+json
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// createExportLink - document a link that returns a file rather than a
+// collection, like the yaml of a resource or the icon of a template,
+// as a GET returning the media type it was served as, tagged with the
+// resource it is on.
+func (g *Generator) createExportLink(target crawlTarget, mediaType string) {
+	path := target.Base + target.Col
+	log.Infof("Document export: %s -> %s as %s", path, target.Link, mediaType)
+
+	schema := &openapi.Schema{Type: "string"}
+	if !strings.HasPrefix(mediaType, "text/") && mediaType != "application/yaml" {
+		schema.Format = "binary"
+	}
+
+	g.swagger.Paths[path] = openapi.PathItem{
+		Parameters: basePathParameters(target.Base),
+		Get: &openapi.Operation{
+			Tags:        []string{g.ownerType(target)},
+			Summary:     target.Col,
+			Description: fmt.Sprintf("`%s` export, served as `%s`", target.Col, mediaType),
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: fmt.Sprintf("Returns the `%s` document.", mediaType),
+					Content: map[string]openapi.MediaType{
+						mediaType: {Schema: schema},
+					},
+				},
+			},
+		},
+	}
+	g.report.crawled(target, "")
+}
+
+// ownerType - the resource type the operations of the resource a link is on
+// are tagged with, the name of the link when it is on an API root
+func (g *Generator) ownerType(target crawlTarget) string {
+	pathItem := g.swagger.Paths[strings.TrimSuffix(target.Base, "/")]
+	for _, method := range openapi.Methods {
+		if op := pathItem.Operation(method); op != nil && len(op.Tags) > 0 {
+			return op.Tags[0]
+		}
+	}
+	return target.Col
+}
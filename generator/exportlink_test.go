@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestGenerateExportLinks(t *testing.T) {
+	swagger, report := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	for path, expected := range map[string]struct {
+		mediaType string
+		format    string
+		tag       string
+	}{
+		"/clusters/{clusterId}/yaml":   {"application/yaml", "", "cluster"},
+		"/clusters/{clusterId}/icon":   {"image/png", "binary", "cluster"},
+		"/projects/{projectId}/readme": {"text/markdown", "", "project"},
+	} {
+		pathItem, ok := swagger.Paths[path]
+		if !ok || pathItem.Get == nil {
+			t.Errorf("expected GET %s, got %+v", path, pathItem)
+			continue
+		}
+		content, ok := pathItem.Get.Responses["200"].Content[expected.mediaType]
+		if !ok || content.Schema == nil || content.Schema.Type != "string" || content.Schema.Format != expected.format {
+			t.Errorf("%s: expected a %s string, got %+v", path, expected.mediaType, pathItem.Get.Responses["200"])
+		}
+		if tags := pathItem.Get.Tags; len(tags) != 1 || tags[0] != expected.tag {
+			t.Errorf("%s: expected the export to be tagged %s, got %v", path, expected.tag, tags)
+		}
+		if len(pathItem.Parameters) != 1 {
+			t.Errorf("%s: expected the resource parameter, got %+v", path, pathItem.Parameters)
+		}
+	}
+
+	if len(report.Failed) != 0 {
+		t.Errorf("expected no failures, got %+v", report.Failed)
+	}
+	if !hasEntry(report.Collections, "/clusters/{clusterId}/yaml", "") {
+		t.Errorf("expected the yaml export in the report, got %+v", report.Collections)
+	}
+}
@@ -0,0 +1,47 @@
+package generator
+
+import (
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+)
+
+// fieldExtensions - norman metadata of a field without an OpenAPI
+// equivalent, e.g. x-rancher-type: reference[project]
+func fieldExtensions(field norman.Field) openapi.Extensions {
+	ext := make(openapi.Extensions)
+	if field.Type != "" {
+		ext["x-rancher-type"] = field.Type
+	}
+	if field.CodeName != "" {
+		ext["x-rancher-code-name"] = field.CodeName
+	}
+	if field.DynamicField {
+		ext["x-rancher-dynamic-field"] = true
+	}
+	if len(ext) == 0 {
+		return nil
+	}
+	return ext
+}
+
+// schemaExtensions - norman metadata of a schema without an OpenAPI
+// equivalent, e.g. x-rancher-plural-name: clusters
+func schemaExtensions(schema norman.Schema) openapi.Extensions {
+	ext := make(openapi.Extensions)
+	if schema.PluralName != "" {
+		ext["x-rancher-plural-name"] = schema.PluralName
+	}
+	if schema.CodeName != "" {
+		ext["x-rancher-code-name"] = schema.CodeName
+	}
+	if len(schema.ResourceMethods) > 0 {
+		ext["x-rancher-resource-methods"] = schema.ResourceMethods
+	}
+	if len(schema.CollectionMethods) > 0 {
+		ext["x-rancher-collection-methods"] = schema.CollectionMethods
+	}
+	if len(ext) == 0 {
+		return nil
+	}
+	return ext
+}
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRancherExtensions(t *testing.T) {
+	swagger, _ := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	cluster := swagger.Components.Schemas["cluster"]
+	if cluster.Extensions["x-rancher-plural-name"] != "clusters" {
+		t.Errorf("expected the plural name of cluster, got %+v", cluster.Extensions)
+	}
+	if methods := cluster.Extensions["x-rancher-resource-methods"]; !reflect.DeepEqual(methods, []string{"GET", "PUT", "DELETE"}) {
+		t.Errorf("expected the resource methods of cluster, got %+v", methods)
+	}
+
+	own := ownSchema(&cluster)
+	for name, expected := range map[string]string{
+		"name":             "dnsLabel",
+		"defaultProjectId": "reference[project]",
+		"labels":           "map[string]",
+	} {
+		if typ := own.Properties[name].Extensions["x-rancher-type"]; typ != expected {
+			t.Errorf("expected cluster.%s to be a %s, got %v", name, expected, typ)
+		}
+	}
+	ref, err := json.Marshal(own.Properties["rancherKubernetesEngineConfig"])
+	expected := `{"description":"Allowed in Methods: ` + "`POST` `PUT`" + `","allOf":[{"$ref":"#/components/schemas/rancherKubernetesEngineConfig"}],"x-rancher-type":"rancherKubernetesEngineConfig"}`
+	if err != nil || string(ref) != expected {
+		t.Errorf("expected the fields next to a $ref in an allOf, got %s %v", ref, err)
+	}
+	if condition := swagger.Components.Schemas["clusterCondition"]; condition.Extensions != nil {
+		t.Errorf("expected no extensions on an embedded type, got %+v", condition.Extensions)
+	}
+}
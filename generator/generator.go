@@ -0,0 +1,371 @@
+// Package generator crawls a Rancher API and documents it as an OpenAPI v3 document.
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	"github.com/rancher/gen-api-docs/samples"
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBase - OpenAPI document the generated paths and schemas are added to
+	DefaultBase = "./data/base.yml"
+	// DefaultMaxDepth - levels of sub collections followed below the root collections
+	DefaultMaxDepth = 5
+)
+
+var pathParameterNames = regexp.MustCompile("{(\\w+)}")
+
+// Options - what to crawl, which collections to document and where to write the docs
+type Options struct {
+	// URL - Rancher API root, e.g. https://rancher.example.com/v3
+	URL string
+	// Source - where the API is read from, defaults to an HTTPSource using Token and Client
+	Source Source
+	// Token - bearer token for the API requests
+	Token string
+	// Client - HTTP client for the API requests, defaults to one skipping TLS verification
+	Client *http.Client
+
+	// Rules - include/exclude rules for collections, nil documents every collection
+	Rules *Rules
+	// MaxDepth - levels of sub collections to follow, 0 only documents the root
+	// collections and a negative depth is not limited
+	MaxDepth int
+
+	// Base - OpenAPI document the paths and schemas are added to, defaults to DefaultBase
+	Base string
+	// Overlays - YAML documents merged over the generated document, in order.
+	// Maps are merged, null removes a key and any other value replaces it.
+	Overlays []string
+
+	// Output - directory swagger.json, report.json and the Exports are written to.
+	// Nothing is written when empty.
+	Output string
+	// Exports - extra formats written to Output, see Export
+	Exports []string
+}
+
+// Generator - crawls the API described by its Options.
+// Generate can be called again to pick up changes, but not concurrently.
+type Generator struct {
+	opts   Options
+	source Source
+
+	swagger *openapi.OpenAPI
+	schemas map[string]map[string]norman.Schema
+	// dynamicLinks - the dynamicSchemas link of each API root that has one
+	dynamicLinks map[string]string
+	// drivers - the driver of each dynamic schema by API root and schema ID
+	drivers map[string]map[string]openapi.Driver
+	// resources - the paths GETting a single resource of each type, shortest first
+	resources map[string][]string
+	graph     *crawlGraph
+	sockets   map[string]string
+	events    map[string]bool
+	rules     *Rules
+	report    *Report
+}
+
+// New - a Generator for opts
+func New(opts Options) *Generator {
+	g := &Generator{
+		opts:   opts,
+		source: opts.Source,
+		rules:  opts.Rules,
+	}
+	if g.opts.Base == "" {
+		g.opts.Base = DefaultBase
+	}
+	if g.source == nil {
+		g.source = NewHTTPSource(opts.Token, opts.Client)
+	}
+	if g.rules == nil {
+		g.rules = &Rules{}
+	}
+	return g
+}
+
+// Schemas - every schema in the API root
+func (g *Generator) Schemas() ([]norman.Schema, error) {
+	return g.source.Schemas(g.opts.URL)
+}
+
+// AllSchemas - the schemas of every API root the last Generate read schemas
+// from, by the path of the root, e.g. /v3, /v3-public or /v3/cluster/c-1.
+// Only the API root before the first Generate. A root that can't be read
+// anymore, like the scope of a removed cluster, has no schemas.
+func (g *Generator) AllSchemas() (map[string][]norman.Schema, error) {
+	roots := []string{g.opts.URL}
+	for root, index := range g.schemas {
+		if root != g.opts.URL && index != nil {
+			roots = append(roots, root)
+		}
+	}
+
+	all := make(map[string][]norman.Schema, len(roots))
+	for _, root := range roots {
+		schemas, err := g.source.Schemas(root)
+		if err != nil {
+			if root == g.opts.URL {
+				return nil, err
+			}
+			log.Warnf("Failed to get Schemas of %s - %v", root, err)
+		}
+		all[rootPath(root)] = schemas
+	}
+	return all, nil
+}
+
+// rootPath - the path of an API root link
+func rootPath(root string) string {
+	u, err := neturl.Parse(root)
+	if err != nil || u.Path == "" {
+		return root
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// Generate - crawl the API and translate it onto the base document.
+// Collections that fail are recorded in the report rather than failing the whole crawl.
+func (g *Generator) Generate() (*openapi.OpenAPI, *Report, error) {
+	log.Debug("Import base")
+	g.swagger = &openapi.OpenAPI{}
+	yamlFile, err := ioutil.ReadFile(g.opts.Base)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = yaml.Unmarshal(yamlFile, g.swagger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Debug("Initialize swagger maps")
+	g.swagger.Paths = make(map[string]openapi.PathItem)
+	g.swagger.Components.Parameters = make(map[string]openapi.Parameter)
+	if g.swagger.Components.Schemas == nil {
+		g.swagger.Components.Schemas = make(map[string]openapi.Schema)
+	}
+
+	g.schemas = make(map[string]map[string]norman.Schema)
+	g.dynamicLinks = make(map[string]string)
+	g.drivers = make(map[string]map[string]openapi.Driver)
+	g.resources = make(map[string][]string)
+	g.graph = newCrawlGraph(g.opts.MaxDepth)
+	g.sockets = make(map[string]string)
+	g.events = make(map[string]bool)
+	g.report = newReport(g.opts.Rules)
+
+	log.Debug("Get Root Collections")
+	for _, root := range g.roots() {
+		if root.Reason == "" {
+			collections, err := g.source.Collections(root.Link)
+			switch {
+			case err != nil && root.Name == managementRoot:
+				return nil, nil, err
+			case err != nil:
+				root.Reason = fmt.Sprintf("Failed to get the %s API root - %v", root.Name, err)
+			default:
+				if link, ok := collections["dynamicSchemas"]; ok {
+					g.dynamicLinks[root.Link] = link
+				}
+				g.pushRoot(root, collections)
+			}
+		}
+		if root.Reason != "" {
+			log.Warnf("Skipped the %s API - %s", root.Name, root.Reason)
+		}
+		g.report.Roots = append(g.report.Roots, root)
+	}
+
+	for target, ok := g.graph.next(); ok; target, ok = g.graph.next() {
+		err = g.parseCollection(target)
+		if err != nil {
+			log.Warnf("Failed to parse %s, %s, %s - %v", target.Col, target.Link, target.Base, err)
+			g.report.failed(target, err)
+		}
+	}
+
+	for _, edge := range g.graph.pruned() {
+		log.Infof("Pruned %s -> %s (%s): %s", edge.From, edge.Name, edge.To, edge.Pruned)
+	}
+	g.report.Pruned = g.graph.pruned()
+	g.createEventSchema()
+
+	for _, root := range g.report.Roots {
+		if root.Public && root.Reason == "" {
+			g.markPublic(root)
+		}
+	}
+
+	g.linkReferences()
+	samples.Add(g.swagger)
+
+	swagger := g.swagger
+	for _, overlay := range g.opts.Overlays {
+		log.Debugf("Apply overlay %s", overlay)
+		swagger, err = applyOverlay(swagger, overlay)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if g.opts.Output != "" {
+		err = Write(swagger, g.report, g.opts.Output, g.opts.Exports)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return swagger, g.report, nil
+}
+
+// skipCollection - check the rules for a collection, recording why it was skipped.
+// Sub collections are only queued under included collections so the
+// include rules are only checked for root collections.
+func (g *Generator) skipCollection(target crawlTarget, schema string) bool {
+	rt := ruleTarget{
+		Collection: target.Col,
+		Path:       target.Base + target.Col,
+		Schema:     schema,
+	}
+	if rule := g.rules.excluded(rt); rule != nil {
+		log.Debugf("Skipped: %s (%s) - %s", rt.Path, rule, rule.Reason)
+		g.report.skipped(target, schema, rule, "")
+		return true
+	}
+	if target.Depth == 0 && !g.rules.included(rt, schema != "") {
+		log.Debugf("Skipped: %s - not included", rt.Path)
+		g.report.skipped(target, schema, nil, "Not matched by any include rule")
+		return true
+	}
+	return false
+}
+
+func (g *Generator) parseCollection(target crawlTarget) error {
+	col, link, base := target.Col, target.Link, target.Base
+
+	if g.skipCollection(target, "") {
+		return nil
+	}
+	log.Infof("Parse Collection: %s -> %s - %s", col, link, base)
+
+	collection, err := g.source.Collection(link)
+	if err != nil {
+		// Export links return YAML, Markdown or images instead of a collection
+		if mediaType, probeErr := g.source.ContentType(link); probeErr == nil && !isJSON(mediaType) {
+			g.createExportLink(target, mediaType)
+			return nil
+		}
+		log.Errorf("Failed to get collection: %v", err)
+		return err
+	}
+
+	if collection.Type != "collection" {
+		log.Debugf("%s is not a collection, skipping - %s %s", col, link, base)
+		g.report.skipped(target, "", nil, "Not a collection")
+		return nil
+	}
+	if g.skipCollection(target, collection.ResourceType) {
+		return nil
+	}
+
+	// Schemas are in the API root the resources are created in, which isn't
+	// always the root the collection was linked from. Read only collections
+	// have no createTypes, use the collection link instead.
+	createLink, ok := collection.CreateTypes[collection.ResourceType]
+	if !ok {
+		createLink = link
+	}
+	schemaRoot := g.schemaRoot(createLink)
+
+	log.Debug("resourceType for collection: ", collection.ResourceType)
+	rSchema, err := g.getSchema(schemaRoot, collection.ResourceType, target.Base+col)
+	if err != nil {
+		return fmt.Errorf("Failed to get Schema for %s/%s - %v", schemaRoot, collection.ResourceType, err)
+	}
+
+	// populate swagger schema objects
+	g.translateSchema(rSchema, schemaRoot)
+	g.report.crawled(target, collection.ResourceType)
+
+	// set schema for collection
+	item := g.itemSchema(collection, rSchema, schemaRoot, target.Base+col)
+	g.createCollectionSchema(col, item)
+
+	// set previous parameters
+	parameters := basePathParameters(base)
+
+	// /{collection}
+	colParameters := parameters
+	colPathItem := openapi.PathItem{
+		Parameters: colParameters,
+	}
+
+	for _, method := range rSchema.CollectionMethods {
+		if method == "GET" {
+			colPathItem.Get = createCollection("GET", col, collection, item)
+			colPathItem.Get.Parameters = createFilterParameters(rSchema)
+		} else if method == "POST" {
+			colPathItem.Post = createCollection("POST", col, collection, item)
+		} else {
+			log.Error("Unknown Collection Method: ", method)
+		}
+	}
+	// /{collection}?action={action}
+	colPathItem.Post = g.createActions(colPathItem.Post, rSchema.CollectionActions, collection.ResourceType, schemaRoot)
+	g.swagger.Paths[fmt.Sprintf("%s%s", base, col)] = colPathItem
+
+	// Resource /{collection}/{id}
+	newPramID := fmt.Sprintf("%sId", collection.ResourceType)
+	g.createPathParameter(newPramID)
+	param := openapi.Parameter{
+		Ref: fmt.Sprintf("#/components/parameters/%s", newPramID),
+	}
+	parameters = append(parameters, param)
+
+	resourcePathItem := openapi.PathItem{
+		Parameters: parameters,
+	}
+	for _, method := range rSchema.ResourceMethods {
+		if method == "GET" {
+			resourcePathItem.Get = createResource("GET", collection.ResourceType, item)
+		} else if method == "PUT" {
+			resourcePathItem.Put = createResource("PUT", collection.ResourceType, item)
+		} else if method == "DELETE" {
+			resourcePathItem.Delete = createResource("DELETE", collection.ResourceType, item)
+		} else {
+			log.Error("Unknown Resource Method: ", method)
+		}
+	}
+	// /{collection}/{id}?action={action}
+	resourcePathItem.Post = g.createActions(nil, rSchema.ResourceActions, collection.ResourceType, schemaRoot)
+	g.swagger.Paths[fmt.Sprintf("%s%s/{%s}", base, col, newPramID)] = resourcePathItem
+	if resourcePathItem.Get != nil {
+		g.addResourcePath(collection.ResourceType, fmt.Sprintf("%s%s/{%s}", base, col, newPramID))
+	}
+
+	if len(collection.Data) > 0 {
+		// take the first one
+		subBase := fmt.Sprintf("%s%s/{%s}/", base, col, newPramID)
+
+		links := collection.Data[0].Links
+		g.graph.see(links["self"], fmt.Sprintf("%s%s/{%s}", base, col, newPramID))
+		for _, subCol := range sortedKeys(links) {
+			if links[subCol] != links["self"] {
+				g.pushLink(subBase, subCol, links[subCol], subBase, target.Depth+1)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rancher/gen-api-docs/fake"
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+)
+
+func newFakeRancher(t *testing.T) *fake.Server {
+	t.Helper()
+	fixture, err := fake.LoadFixture("../testdata/rancher.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewServer(fixture)
+}
+
+func defaultRules(t *testing.T) *Rules {
+	t.Helper()
+	rules, err := LoadRules("../data/rules.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rules
+}
+
+func generateFake(t *testing.T, maxDepth int, rules *Rules) (*openapi.OpenAPI, *Report) {
+	t.Helper()
+	srv := newFakeRancher(t)
+	defer srv.Close()
+
+	swagger, report, err := New(Options{
+		URL:      srv.URL + "/v3",
+		Rules:    rules,
+		MaxDepth: maxDepth,
+		Base:     "../data/base.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return swagger, report
+}
+
+func assertPaths(t *testing.T, swagger *openapi.OpenAPI, expected []string, unexpected []string) {
+	t.Helper()
+	for _, path := range expected {
+		if _, ok := swagger.Paths[path]; !ok {
+			t.Errorf("missing path %s", path)
+		}
+	}
+	for _, path := range unexpected {
+		if _, ok := swagger.Paths[path]; ok {
+			t.Errorf("unexpected path %s", path)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	swagger, report := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	assertPaths(t, swagger, []string{
+		"/clusters",
+		"/clusters/{clusterId}",
+		"/clusters/{clusterId}/nodes",
+		"/clusters/{clusterId}/nodes/{nodeId}",
+		"/nodes",
+		"/nodes/{nodeId}",
+		"/projects",
+		"/projects/{projectId}",
+		"/clusters/{clusterId}/projects",
+	}, []string{
+		"/clusters/{clusterId}/nodes/{nodeId}/cluster",
+	})
+
+	clusters := swagger.Paths["/clusters"]
+	if clusters.Get == nil || clusters.Post == nil {
+		t.Errorf("expected GET and POST on /clusters, got %+v", clusters)
+	}
+	cluster := swagger.Paths["/clusters/{clusterId}"]
+	if cluster.Get == nil || cluster.Put == nil || cluster.Delete == nil {
+		t.Errorf("expected GET, PUT and DELETE on /clusters/{clusterId}, got %+v", cluster)
+	}
+	if len(clusters.Get.Tags) != 1 || clusters.Get.Tags[0] != "cluster" {
+		t.Errorf("expected /clusters to be tagged cluster, got %v", clusters.Get.Tags)
+	}
+	filters := make([]string, 0)
+	for _, p := range clusters.Get.Parameters {
+		filters = append(filters, p.Name)
+	}
+	if strings.Join(filters, ",") != "name,name_ne,name_in,state" {
+		t.Errorf("unexpected filters on /clusters: %v", filters)
+	}
+	actions := swagger.Paths["/clusters/{clusterId}"].Post
+	if actions == nil {
+		t.Fatal("expected the actions of a cluster as POST /clusters/{clusterId}")
+	}
+	if len(actions.Parameters) != 1 || actions.Parameters[0].Name != "action" || !actions.Parameters[0].Required || len(actions.Parameters[0].Schema.Enum) != len(actions.Actions()) {
+		t.Errorf("expected a required action query parameter with every action, got %+v", actions.Parameters)
+	}
+	if kubeconfig := actions.Actions()["generateKubeconfig"]; kubeconfig.Output == nil || kubeconfig.Output.Ref != "#/components/schemas/generateKubeConfigOutput" {
+		t.Errorf("expected generateKubeconfig action returning generateKubeConfigOutput, got %+v", kubeconfig)
+	}
+	if rotate := actions.Actions()["rotateCertificates"]; rotate.Input == nil || rotate.Input.Ref != "#/components/schemas/rotateCertificateInput" {
+		t.Errorf("expected rotateCertificates action taking rotateCertificateInput, got %+v", rotate)
+	}
+	if actions.RequestBody == nil || actions.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/rotateCertificateInput" {
+		t.Errorf("expected the only action input as the request body, got %+v", actions.RequestBody)
+	}
+	for path := range swagger.Paths {
+		if strings.Contains(path, "?") {
+			t.Errorf("expected no query in path %s", path)
+		}
+	}
+
+	nodes := swagger.Paths["/clusters/{clusterId}/nodes"]
+	if len(nodes.Parameters) != 1 || nodes.Parameters[0].Ref != "#/components/parameters/clusterId" {
+		t.Errorf("expected clusterId parameter on nested collection, got %+v", nodes.Parameters)
+	}
+
+	clusterSchema, ok := swagger.Components.Schemas["cluster"]
+	if !ok {
+		t.Fatal("missing cluster schema")
+	}
+	if len(clusterSchema.AllOf) != 2 || clusterSchema.AllOf[0].Ref != "#/components/schemas/resource" {
+		t.Fatalf("expected cluster to extend resource, got %+v", clusterSchema)
+	}
+	schema := ownSchema(&clusterSchema)
+	for name, expected := range map[string]openapi.Schema{
+		"name":                          {Type: "string"},
+		"labels":                        {Type: "object"},
+		"nodeCount":                     {Type: "integer"},
+		"rancherKubernetesEngineConfig": {Ref: "#/components/schemas/rancherKubernetesEngineConfig"},
+	} {
+		p := schema.Properties[name]
+		if p.Type != expected.Type || p.Format != expected.Format || p.Ref != expected.Ref {
+			t.Errorf("cluster.%s: expected %+v, got %+v", name, expected, p)
+		}
+	}
+	for _, name := range []string{"state", "created"} {
+		if _, ok := schema.Properties[name]; ok {
+			t.Errorf("expected cluster.%s to be left to resource", name)
+		}
+	}
+	if items := schema.Properties["conditions"].Items; items == nil || items.Ref != "#/components/schemas/clusterCondition" {
+		t.Errorf("cluster.conditions should be an array of clusterCondition, got %+v", items)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "name" {
+		t.Errorf("expected cluster to require name, got %v", schema.Required)
+	}
+	for _, name := range []string{"clusterCondition", "rancherKubernetesEngineConfig", "node", "project", "clusters", "generateKubeConfigOutput", "rotateCertificateInput"} {
+		if _, ok := swagger.Components.Schemas[name]; !ok {
+			t.Errorf("missing schema %s", name)
+		}
+	}
+
+	// projects?clusterId=c-1 is a filtered view, not the projects collection
+	if hasEdge(report.Pruned, "/clusters/{clusterId}/", "projects") {
+		t.Errorf("expected filtered projects link to be followed, got %+v", report.Pruned)
+	}
+	if !hasEdge(report.Pruned, "/clusters/{clusterId}/nodes/{nodeId}/", "cluster") {
+		t.Errorf("expected link back to the parent cluster to be pruned, got %+v", report.Pruned)
+	}
+	if len(report.Failed) != 0 {
+		t.Errorf("expected no failures, got %+v", report.Failed)
+	}
+}
+
+func TestGenerateMaxDepth(t *testing.T) {
+	swagger, report := generateFake(t, 0, defaultRules(t))
+
+	assertPaths(t, swagger, []string{
+		"/clusters",
+		"/nodes",
+	}, []string{
+		"/clusters/{clusterId}/nodes",
+	})
+	if !hasEdge(report.Pruned, "/clusters/{clusterId}/", "nodes") {
+		t.Errorf("expected nested nodes to be pruned, got %+v", report.Pruned)
+	}
+}
+
+func TestGenerateIncludeRules(t *testing.T) {
+	rules := defaultRules(t)
+	rules.Include = []Rule{{Schema: "cluster", Reason: "clusters only"}}
+	if err := rules.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	swagger, report := generateFake(t, DefaultMaxDepth, rules)
+
+	assertPaths(t, swagger, []string{
+		"/clusters",
+		"/clusters/{clusterId}/nodes",
+	}, []string{
+		"/nodes",
+		"/projects",
+	})
+	if !hasEntry(report.Skipped, "/projects", "Not matched by any include rule") {
+		t.Errorf("expected /projects to be skipped, got %+v", report.Skipped)
+	}
+}
+
+func hasEntry(entries []ReportEntry, path string, reason string) bool {
+	for _, entry := range entries {
+		if entry.Path == path && entry.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEdge(edges []Edge, from string, name string) bool {
+	for _, edge := range edges {
+		if edge.From == from && edge.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGenerateOverlaysAndOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen-api-docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	overlay := filepath.Join(dir, "overlay.yml")
+	err = ioutil.WriteFile(overlay, []byte(`
+info:
+  title: Rancher Cluster API
+paths:
+  /projects: null
+components:
+  schemas:
+    cluster:
+      description: A Kubernetes cluster managed by Rancher
+      x-docs-owner: clusters team
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newFakeRancher(t)
+	defer srv.Close()
+	output := filepath.Join(dir, "build")
+	swagger, _, err := New(Options{
+		URL:      srv.URL + "/v3",
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+		Overlays: []string{overlay},
+		Output:   output,
+		Exports:  []string{"markdown"},
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if swagger.Info.Title != "Rancher Cluster API" {
+		t.Errorf("expected the overlay title, got %s", swagger.Info.Title)
+	}
+	assertPaths(t, swagger, []string{"/clusters", "/nodes"}, []string{"/projects"})
+	cluster := swagger.Components.Schemas["cluster"]
+	if cluster.Description != "A Kubernetes cluster managed by Rancher" || ownSchema(&cluster).Properties["name"].Type != "string" {
+		t.Errorf("expected the overlay to be merged into the cluster schema, got %+v", cluster)
+	}
+	if cluster.Extensions["x-docs-owner"] != "clusters team" || cluster.Extensions["x-rancher-plural-name"] != "clusters" {
+		t.Errorf("expected the extensions to be kept and merged, got %+v", cluster.Extensions)
+	}
+
+	for _, file := range []string{"swagger.json", "report.json", "markdown/cluster.md"} {
+		if _, err := os.Stat(filepath.Join(output, file)); err != nil {
+			t.Errorf("expected %s to be written - %v", file, err)
+		}
+	}
+}
+
+func TestCreateCollectionActions(t *testing.T) {
+	g := &Generator{swagger: &openapi.OpenAPI{Components: openapi.Components{Schemas: map[string]openapi.Schema{
+		"importInput":  {Type: "object"},
+		"importOutput": {Type: "object"},
+	}}}}
+	create := createCollection("POST", "clusters", &Collection{Collection: &norman.Collection{ResourceType: "cluster"}}, "cluster")
+
+	post := g.createActions(create, map[string]norman.Action{
+		"import": {Input: "importInput", Output: "importOutput"},
+	}, "cluster", "")
+	if post != create {
+		t.Fatal("expected the actions on the create POST")
+	}
+	if len(post.Parameters) != 1 || post.Parameters[0].Required {
+		t.Errorf("expected an optional action parameter next to create, got %+v", post.Parameters)
+	}
+	body := post.RequestBody.Content["application/json"].Schema
+	if len(body.OneOf) != 2 || body.OneOf[0].Ref != "#/components/schemas/cluster" || body.OneOf[1].Ref != "#/components/schemas/importInput" {
+		t.Errorf("expected the create body first of the oneOf, got %+v", body)
+	}
+	response := post.Responses["200"].Content["application/json"].Schema
+	if len(response.OneOf) != 2 || response.OneOf[1].Ref != "#/components/schemas/importOutput" {
+		t.Errorf("expected the created object or the action output, got %+v", response)
+	}
+
+	if g.createActions(nil, nil, "cluster", "") != nil {
+		t.Error("expected no POST without actions")
+	}
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+)
+
+// resourceSchema - component in the base document with the fields every
+// resource has, id, type, links, actions, name, state, uuid and created
+const resourceSchema = "resource"
+
+// baseSchema - the component a schema extends, its norman BaseType or the
+// resource component for the schema of a resource. Empty for embedded types.
+func (g *Generator) baseSchema(rSchema norman.Schema, schemaRoot string) string {
+	if rSchema.BaseType != "" && rSchema.BaseType != rSchema.ID && g.resolveSchema(rSchema.BaseType, schemaRoot, rSchema.ID) {
+		return rSchema.BaseType
+	}
+	if _, ok := g.swagger.Components.Schemas[resourceSchema]; ok && len(rSchema.ResourceMethods) > 0 {
+		return resourceSchema
+	}
+	return ""
+}
+
+// extend - schema as allOf the base and its own properties.
+// Properties the same as the base's are left to the base.
+func (g *Generator) extend(base string, schema openapi.Schema) openapi.Schema {
+	inherited := flatProperties(g.swagger.Components.Schemas, g.swagger.Components.Schemas[base], map[string]bool{base: true})
+
+	own := openapi.Schema{
+		Type:       "object",
+		Properties: make(map[string]openapi.Schema),
+		Required:   schema.Required,
+	}
+	for name, property := range schema.Properties {
+		if baseProperty, ok := inherited[name]; ok && sameProperty(baseProperty, property) {
+			continue
+		}
+		own.Properties[name] = property
+	}
+
+	return openapi.Schema{
+		AllOf: []openapi.Schema{
+			{Ref: fmt.Sprintf("#/components/schemas/%s", base)},
+			own,
+		},
+	}
+}
+
+// sameProperty - the properties only differ in their extensions,
+// the norman metadata of a field doesn't need repeating
+func sameProperty(a openapi.Schema, b openapi.Schema) bool {
+	a.Extensions, b.Extensions = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// ownSchema - the part of a component with the properties of its own,
+// the last allOf part of a schema extending a base
+func ownSchema(schema *openapi.Schema) *openapi.Schema {
+	if len(schema.AllOf) > 0 && len(schema.Properties) == 0 {
+		return &schema.AllOf[len(schema.AllOf)-1]
+	}
+	return schema
+}
+
+// flatProperties - every property of schema, including the ones of the
+// schemas it extends with allOf
+func flatProperties(schemas map[string]openapi.Schema, schema openapi.Schema, seen map[string]bool) map[string]openapi.Schema {
+	properties := make(map[string]openapi.Schema)
+	for _, part := range schema.AllOf {
+		if part.Ref != "" {
+			name := strings.TrimPrefix(part.Ref, "#/components/schemas/")
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			part = schemas[name]
+		}
+		for name, property := range flatProperties(schemas, part, seen) {
+			properties[name] = property
+		}
+	}
+	for name, property := range schema.Properties {
+		properties[name] = property
+	}
+	return properties
+}
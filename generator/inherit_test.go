@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestGenerateBaseTypes(t *testing.T) {
+	swagger, _ := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	for name, base := range map[string]string{
+		"githubConfig": "authConfig",
+		"localConfig":  "authConfig",
+		"authConfig":   "resource",
+		"node":         "resource",
+	} {
+		schema := swagger.Components.Schemas[name]
+		if len(schema.AllOf) != 2 || schema.AllOf[0].Ref != "#/components/schemas/"+base {
+			t.Errorf("expected %s to extend %s, got %+v", name, base, schema)
+		}
+	}
+
+	githubConfig := swagger.Components.Schemas["githubConfig"]
+	own := ownSchema(&githubConfig)
+	for _, name := range []string{"enabled", "accessMode"} {
+		if _, ok := own.Properties[name]; ok {
+			t.Errorf("expected githubConfig.%s to be left to authConfig", name)
+		}
+	}
+	for _, name := range []string{"hostname", "clientId"} {
+		if _, ok := own.Properties[name]; !ok {
+			t.Errorf("expected githubConfig to have its own %s, got %+v", name, own.Properties)
+		}
+	}
+	if len(own.Required) != 1 || own.Required[0] != "clientId" {
+		t.Errorf("expected githubConfig to require clientId, got %v", own.Required)
+	}
+
+	properties := flatProperties(swagger.Components.Schemas, githubConfig, map[string]bool{})
+	for _, name := range []string{"id", "links", "state", "enabled", "accessMode", "hostname"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("expected githubConfig to have %s through its bases, got %+v", name, properties)
+		}
+	}
+
+	if condition := swagger.Components.Schemas["clusterCondition"]; len(condition.AllOf) != 0 {
+		t.Errorf("expected the embedded clusterCondition to stand alone, got %+v", condition)
+	}
+}
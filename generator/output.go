@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rancher/gen-api-docs/asyncapi"
+	"github.com/rancher/gen-api-docs/clients"
+	"github.com/rancher/gen-api-docs/jsonschema"
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	"github.com/rancher/gen-api-docs/render"
+	log "github.com/sirupsen/logrus"
+)
+
+// Write - write swagger.json, report.json and each export format to dir
+func Write(swagger *openapi.OpenAPI, report *Report, dir string, exports []string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	// Render swagger doc
+	out, err := json.Marshal(swagger)
+	if err != nil {
+		return err
+	}
+	log.Debug(string(out))
+	err = ioutil.WriteFile(filepath.Join(dir, "swagger.json"), out, 0644)
+	if err != nil {
+		return err
+	}
+
+	if report != nil {
+		err = report.write(filepath.Join(dir, "report.json"))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, format := range exports {
+		err = Export(swagger, dir, format)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Export - write swagger in another format to dir/<format>:
+// markdown, html, jsonschema, postman, insomnia or asyncapi
+func Export(swagger *openapi.OpenAPI, dir string, format string) error {
+	format = strings.TrimSpace(format)
+	out := filepath.Join(dir, format)
+	switch format {
+	case "":
+		return nil
+	case "markdown":
+		log.Debug("Export markdown")
+		return render.Markdown(swagger, out)
+	case "html":
+		log.Debug("Export html")
+		return render.HTML(swagger, out)
+	case "jsonschema":
+		log.Debug("Export JSON Schema")
+		return jsonschema.Write(swagger, out)
+	case "postman":
+		log.Debug("Export Postman collection")
+		return clients.WritePostman(swagger, out)
+	case "insomnia":
+		log.Debug("Export Insomnia collection")
+		return clients.WriteInsomnia(swagger, out)
+	case "asyncapi":
+		log.Debug("Export AsyncAPI document")
+		return asyncapi.Write(swagger, out)
+	}
+	return fmt.Errorf("Unknown export format: %s", format)
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+// applyOverlay - swagger with the YAML document in file merged over it
+func applyOverlay(swagger *openapi.OpenAPI, file string) (*openapi.OpenAPI, error) {
+	yamlOverlay, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	overlay := make(map[interface{}]interface{})
+	err = yaml.Unmarshal(yamlOverlay, overlay)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse overlay %s - %v", file, err)
+	}
+
+	yamlDoc, err := yaml.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[interface{}]interface{})
+	err = yaml.Unmarshal(yamlDoc, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := yaml.Marshal(merge(doc, overlay))
+	if err != nil {
+		return nil, err
+	}
+	out := &openapi.OpenAPI{}
+	err = yaml.Unmarshal(merged, out)
+	if err != nil {
+		return nil, fmt.Errorf("Overlay %s is not a valid OpenAPI document - %v", file, err)
+	}
+	return out, nil
+}
+
+// merge - merge overlay into doc.
+// Maps are merged, null removes a key and any other value replaces it.
+func merge(doc, overlay map[interface{}]interface{}) map[interface{}]interface{} {
+	for key, value := range overlay {
+		if value == nil {
+			delete(doc, key)
+			continue
+		}
+		docMap, docOk := doc[key].(map[interface{}]interface{})
+		overlayMap, overlayOk := value.(map[interface{}]interface{})
+		if docOk && overlayOk {
+			doc[key] = merge(docMap, overlayMap)
+			continue
+		}
+		doc[key] = value
+	}
+	return doc
+}
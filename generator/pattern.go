@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	norman "github.com/rancher/norman/types"
+)
+
+const (
+	lowerChars = "abcdefghijklmnopqrstuvwxyz"
+	digitChars = "0123456789"
+)
+
+// label - a DNS label as the characters allowed first, inside and last
+type label struct {
+	first, inner, last string
+}
+
+// dnsType - a norman string type validated as a DNS name like Kubernetes does
+type dnsType struct {
+	label       label
+	subdomain   bool
+	maxLength   int64
+	description string
+}
+
+// dnsTypes - RFC 1123 labels, RFC 1035 labels and RFC 1123 subdomains
+var dnsTypes = map[string]dnsType{
+	"dnsLabel": {
+		label:       label{lowerChars + digitChars, "-" + lowerChars + digitChars, lowerChars + digitChars},
+		maxLength:   63,
+		description: "Must be a valid DNS label",
+	},
+	"dnsLabelRestricted": {
+		label:       label{lowerChars, "-" + lowerChars + digitChars, lowerChars + digitChars},
+		maxLength:   63,
+		description: "Must be a valid DNS label starting with a letter",
+	},
+	"hostname": {
+		label:       label{lowerChars + digitChars, "-" + lowerChars + digitChars, lowerChars + digitChars},
+		subdomain:   true,
+		maxLength:   253,
+		description: "Must be a valid Hostname",
+	},
+}
+
+// fieldPattern - anchored regex of the values norman accepts for a string
+// field, its ValidChars, InvalidChars and DNS type. Empty for any value.
+func fieldPattern(field norman.Field) string {
+	allowed := func(r rune) bool {
+		return (field.ValidChars == "" || strings.ContainsRune(field.ValidChars, r)) &&
+			!strings.ContainsRune(field.InvalidChars, r)
+	}
+
+	if dns, ok := dnsTypes[field.Type]; ok {
+		l := dns.label.pattern(allowed)
+		if dns.subdomain && allowed('.') {
+			return fmt.Sprintf("^%s(\\.%s)*$", l, l)
+		}
+		return fmt.Sprintf("^%s$", l)
+	}
+
+	switch {
+	case field.ValidChars != "":
+		return fmt.Sprintf("^%s*$", charClass(field.ValidChars, allowed))
+	case field.InvalidChars != "":
+		return fmt.Sprintf("^[^%s]*$", classChars(field.InvalidChars))
+	}
+	return ""
+}
+
+// pattern - the label with only the allowed characters
+func (l label) pattern(allowed func(rune) bool) string {
+	return fmt.Sprintf("%s(%s*%s)?", charClass(l.first, allowed), charClass(l.inner, allowed), charClass(l.last, allowed))
+}
+
+// charClass - character class of the allowed chars, one matching
+// nothing when none are
+func charClass(chars string, allowed func(rune) bool) string {
+	kept := make([]rune, 0, len(chars))
+	for _, r := range chars {
+		if allowed(r) {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == 0 {
+		return "[^\\s\\S]"
+	}
+	return fmt.Sprintf("[%s]", classChars(string(kept)))
+}
+
+// classChars - chars escaped for a character class, runs of three or
+// more consecutive characters as a range, e.g. a-z
+func classChars(chars string) string {
+	runes := []rune(chars)
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	unique := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		if i == 0 || r != runes[i-1] {
+			unique = append(unique, r)
+		}
+	}
+
+	b := &strings.Builder{}
+	for i := 0; i < len(unique); {
+		j := i
+		for j+1 < len(unique) && unique[j+1] == unique[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			b.WriteString(classChar(unique[i]) + "-" + classChar(unique[j]))
+		} else {
+			for _, r := range unique[i : j+1] {
+				b.WriteString(classChar(r))
+			}
+		}
+		i = j + 1
+	}
+	return b.String()
+}
+
+// classChar - r escaped for a character class
+func classChar(r rune) string {
+	if strings.ContainsRune(`\]^-[`, r) {
+		return `\` + string(r)
+	}
+	return string(r)
+}
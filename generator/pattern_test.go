@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/rancher/norman/parse/builder"
+	norman "github.com/rancher/norman/types"
+)
+
+func TestFieldPattern(t *testing.T) {
+	inputs := []string{
+		"a", "abc", "my-name", "123-abc", "-abc", "abc-", "a.b", "a..b", "example.com", ".com",
+		"Abc", "a_b", "a b", "a]b", `a\b`, "a^b", "a-b-c", "0", "-", "ü", "a/b", "1.2.3",
+	}
+
+	for _, field := range []norman.Field{
+		{Type: "string", ValidChars: "abc"},
+		{Type: "string", ValidChars: "a-c]"},
+		{Type: "string", ValidChars: `abcdefghijklmnopqrstuvwxyz0123456789\^`},
+		{Type: "string", InvalidChars: "/ "},
+		{Type: "string", InvalidChars: `-]\^`},
+		{Type: "string", ValidChars: "abc-", InvalidChars: "-"},
+		{Type: "string", ValidChars: "xyz"},
+		{Type: "dnsLabel"},
+		{Type: "dnsLabel", InvalidChars: "-"},
+		{Type: "dnsLabelRestricted"},
+		{Type: "hostname"},
+		{Type: "hostname", InvalidChars: "."},
+		{Type: "hostname", ValidChars: "abcxyz.-0123"},
+	} {
+		pattern := fieldPattern(field)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			t.Errorf("expected a valid pattern for %+v, got %s - %v", field, pattern, err)
+			continue
+		}
+		for _, input := range inputs {
+			expected := accepts(field, input)
+			if actual := re.MatchString(input); actual != expected {
+				t.Errorf("expected %s of %+v to match %q: %v, got %v", pattern, field, input, expected, actual)
+			}
+		}
+	}
+
+	if pattern := fieldPattern(norman.Field{Type: "string"}); pattern != "" {
+		t.Errorf("expected no pattern without valid or invalid chars, got %s", pattern)
+	}
+	if pattern := fieldPattern(norman.Field{Type: "string", ValidChars: "abcdxz"}); pattern != "^[a-dxz]*$" {
+		t.Errorf("expected consecutive chars as a range, got %s", pattern)
+	}
+}
+
+// accepts - norman validates input for field on create
+func accepts(field norman.Field, input string) bool {
+	value, err := builder.ConvertSimple(field.Type, input, builder.Create)
+	if err != nil {
+		return false
+	}
+	return builder.CheckFieldCriteria("field", field, value) == nil
+}
+
+func TestGenerateFieldPatterns(t *testing.T) {
+	swagger, _ := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	cluster := swagger.Components.Schemas["cluster"]
+	name := ownSchema(&cluster).Properties["name"]
+	if name.Pattern != fieldPattern(norman.Field{Type: "dnsLabel"}) || name.MaxLength == nil || *name.MaxLength != 63 {
+		t.Errorf("expected cluster.name to be a DNS label, got %+v", name)
+	}
+}
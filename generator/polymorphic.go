@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// itemSchema - the schema the resources of a collection are documented as.
+// Usually the resource type, but some collections hold more than one type,
+// e.g. authConfigs holds a githubConfig and a localConfig. Those, and
+// resources configured per driver like node templates, are documented as
+// <resourceType>OneOf, a oneOf the concrete schemas with a discriminator.
+func (g *Generator) itemSchema(collection *Collection, rSchema norman.Schema, schemaRoot string, referencedBy string) string {
+	resourceType := collection.ResourceType
+
+	mapping := g.typeVariants(collection, schemaRoot, referencedBy)
+	propertyName := "type"
+	if len(mapping) == 0 {
+		mapping = g.driverVariants(rSchema)
+		propertyName = "driver"
+	}
+	if len(mapping) == 0 {
+		return resourceType
+	}
+
+	values := make([]string, 0, len(mapping))
+	for value := range mapping {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	oneOf := make([]openapi.Schema, 0, len(values))
+	for _, value := range values {
+		oneOf = append(oneOf, openapi.Schema{Ref: mapping[value]})
+	}
+
+	name := fmt.Sprintf("%sOneOf", resourceType)
+	log.Debugf("%s is one of %s by %s", name, strings.Join(values, ", "), propertyName)
+	g.swagger.Components.Schemas[name] = openapi.Schema{
+		Description: fmt.Sprintf("One of the `%s` types, told apart by `%s`.", resourceType, propertyName),
+		OneOf:       oneOf,
+		Discriminator: &openapi.Discriminator{
+			PropertyName: propertyName,
+			Mapping:      mapping,
+		},
+	}
+	return name
+}
+
+// typeVariants - the concrete types seen in the collection or listed in its
+// createTypes, when they aren't all the resource type. Each schema gets the
+// type property the discriminator reads.
+func (g *Generator) typeVariants(collection *Collection, schemaRoot string, referencedBy string) map[string]string {
+	types := make(map[string]bool)
+	observed := false
+	for _, resource := range collection.Data {
+		if resource.Type != "" {
+			types[resource.Type] = true
+			observed = observed || resource.Type == collection.ResourceType
+		}
+	}
+	for createType := range collection.CreateTypes {
+		types[createType] = true
+	}
+	// The resource type is only a variant of itself when resources of it are listed
+	if !observed {
+		delete(types, collection.ResourceType)
+	}
+	if len(types) == 0 || (len(types) == 1 && types[collection.ResourceType]) {
+		return nil
+	}
+
+	mapping := make(map[string]string)
+	for t := range types {
+		if !g.resolveSchema(t, schemaRoot, referencedBy) {
+			continue
+		}
+		schema := g.swagger.Components.Schemas[t]
+		own := ownSchema(&schema)
+		if property, ok := own.Properties["type"]; !ok || len(property.Enum) == 0 {
+			if own.Properties == nil {
+				own.Properties = make(map[string]openapi.Schema)
+			}
+			own.Properties["type"] = openapi.Schema{
+				Type: "string",
+				Enum: []interface{}{t},
+			}
+			g.swagger.Components.Schemas[t] = schema
+		}
+		mapping[t] = fmt.Sprintf("#/components/schemas/%s", t)
+	}
+	return mapping
+}
+
+// driverVariants - a schema with a driver field and a <driver>Config field for
+// each driver, like nodeTemplate, as the resource type with the config of one driver
+func (g *Generator) driverVariants(rSchema norman.Schema) map[string]string {
+	if _, ok := rSchema.ResourceFields["driver"]; !ok {
+		return nil
+	}
+
+	mapping := make(map[string]string)
+	for name, field := range rSchema.ResourceFields {
+		if !strings.HasSuffix(name, "Config") || field.Type != name {
+			continue
+		}
+		driver := strings.TrimSuffix(name, "Config")
+		variant := fmt.Sprintf("%s%s", driver, strings.ToUpper(rSchema.ID[:1])+rSchema.ID[1:])
+		g.swagger.Components.Schemas[variant] = openapi.Schema{
+			Description: fmt.Sprintf("`%s` using the `%s` driver.", rSchema.ID, driver),
+			AllOf: []openapi.Schema{
+				{Ref: fmt.Sprintf("#/components/schemas/%s", rSchema.ID)},
+				{
+					Type:     "object",
+					Required: []string{name},
+					Properties: map[string]openapi.Schema{
+						"driver": {Type: "string", Enum: []interface{}{driver}},
+					},
+				},
+			},
+		}
+		mapping[driver] = fmt.Sprintf("#/components/schemas/%s", variant)
+	}
+	return mapping
+}
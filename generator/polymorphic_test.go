@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestGeneratePolymorphicCollections(t *testing.T) {
+	swagger, report := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	authConfigs := swagger.Paths["/authConfigs"]
+	if authConfigs.Get == nil {
+		t.Fatalf("expected GET /authConfigs, got %+v", authConfigs)
+	}
+	if items := swagger.Components.Schemas["authConfigs"].Properties["data"].Items; items == nil || items.Ref != "#/components/schemas/authConfigOneOf" {
+		t.Errorf("expected the authConfigs to be authConfigOneOf, got %+v", items)
+	}
+	if ref := swagger.Paths["/authConfigs/{authConfigId}"].Get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/authConfigOneOf" {
+		t.Errorf("expected an authConfig to be authConfigOneOf, got %s", ref)
+	}
+
+	oneOf := swagger.Components.Schemas["authConfigOneOf"]
+	if len(oneOf.OneOf) != 2 || oneOf.OneOf[0].Ref != "#/components/schemas/githubConfig" || oneOf.OneOf[1].Ref != "#/components/schemas/localConfig" {
+		t.Errorf("expected oneOf githubConfig and localConfig, got %+v", oneOf.OneOf)
+	}
+	if oneOf.Discriminator == nil || oneOf.Discriminator.PropertyName != "type" || oneOf.Discriminator.Mapping["localConfig"] != "#/components/schemas/localConfig" {
+		t.Errorf("expected a discriminator on type, got %+v", oneOf.Discriminator)
+	}
+	githubConfig := swagger.Components.Schemas["githubConfig"]
+	if typ := ownSchema(&githubConfig).Properties["type"]; len(typ.Enum) != 1 || typ.Enum[0] != "githubConfig" {
+		t.Errorf("expected githubConfig to have its type, got %+v", typ)
+	}
+	authConfig := swagger.Components.Schemas["authConfig"]
+	if _, ok := ownSchema(&authConfig).Properties["type"]; ok {
+		t.Error("expected authConfig to be left alone, none of the configs are one")
+	}
+
+	nodeTemplates := swagger.Components.Schemas["nodeTemplateOneOf"]
+	if nodeTemplates.Discriminator == nil || nodeTemplates.Discriminator.PropertyName != "driver" || len(nodeTemplates.OneOf) != 2 {
+		t.Fatalf("expected node templates to be told apart by driver, got %+v", nodeTemplates)
+	}
+	if ref := nodeTemplates.Discriminator.Mapping["amazonec2"]; ref != "#/components/schemas/amazonec2NodeTemplate" {
+		t.Errorf("expected the amazonec2 driver to map to amazonec2NodeTemplate, got %s", ref)
+	}
+	amazon := swagger.Components.Schemas["amazonec2NodeTemplate"]
+	if len(amazon.AllOf) != 2 || amazon.AllOf[0].Ref != "#/components/schemas/nodeTemplate" || amazon.AllOf[1].Required[0] != "amazonec2Config" {
+		t.Errorf("expected nodeTemplate with an amazonec2Config, got %+v", amazon)
+	}
+	if ref := swagger.Paths["/nodeTemplates"].Post.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/nodeTemplateOneOf" {
+		t.Errorf("expected to create a nodeTemplateOneOf, got %s", ref)
+	}
+
+	if _, ok := swagger.Components.Schemas["clusterOneOf"]; ok {
+		t.Error("expected clusters to have a single type")
+	}
+	if len(report.Failed) != 0 {
+		t.Errorf("expected no failures, got %+v", report.Failed)
+	}
+}
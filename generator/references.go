@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+// referenceType - norman type of a field holding the id of another
+// resource, reference[project] or array[reference[/v3/schemas/project]]
+var referenceType = regexp.MustCompile(`^(array\[)?reference\[([a-zA-Z0-9/]+)\]\]?$`)
+
+// pathParameter - {clusterId} in a path template
+var pathParameter = regexp.MustCompile(`{(\w+)}`)
+
+// referencedSchema - the schema ID a reference is to, the last part of
+// a schema link like /v3/cluster/schemas/namespace
+func referencedSchema(ref string) string {
+	return path.Base(ref)
+}
+
+// addResourcePath - record a path GETting a single resource of resourceType
+func (g *Generator) addResourcePath(resourceType string, resourcePath string) {
+	for _, p := range g.resources[resourceType] {
+		if p == resourcePath {
+			return
+		}
+	}
+	g.resources[resourceType] = append(g.resources[resourceType], resourcePath)
+	sort.Slice(g.resources[resourceType], func(i, j int) bool {
+		a, b := g.resources[resourceType][i], g.resources[resourceType][j]
+		if strings.Count(a, "/") != strings.Count(b, "/") {
+			return strings.Count(a, "/") < strings.Count(b, "/")
+		}
+		return a < b
+	})
+}
+
+// linkReferences - point the reference fields of every schema to the schema
+// and path of the resource they reference, and add a link to each response
+// with a reference that can be followed
+func (g *Generator) linkReferences() {
+	for name, schema := range g.swagger.Components.Schemas {
+		own := ownSchema(&schema)
+		for field, property := range own.Properties {
+			own.Properties[field] = g.linkReference(property)
+		}
+		g.swagger.Components.Schemas[name] = schema
+	}
+
+	for _, name := range sortedPaths(g.swagger.Paths) {
+		pathItem := g.swagger.Paths[name]
+		ops := []*openapi.Operation{pathItem.Post}
+		// the GET of a collection returns a list of resources
+		if strings.HasSuffix(name, "}") {
+			ops = append(ops, pathItem.Get, pathItem.Put)
+		}
+		for _, op := range ops {
+			if op == nil {
+				continue
+			}
+			response, ok := op.Responses["200"]
+			if !ok {
+				continue
+			}
+			links := g.responseLinks(name, response)
+			if len(links) > 0 {
+				response.Links = links
+				op.Responses["200"] = response
+			}
+		}
+	}
+}
+
+// linkReference - a reference field with the x-rancher-reference extension
+// and its description linked to the referenced schema
+func (g *Generator) linkReference(property openapi.Schema) openapi.Schema {
+	match := referenceType.FindStringSubmatch(property.RancherType())
+	if match == nil {
+		return property
+	}
+	target := referencedSchema(match[2])
+	if _, ok := g.swagger.Components.Schemas[target]; !ok {
+		return property
+	}
+
+	schemaRef := fmt.Sprintf("#/components/schemas/%s", target)
+	reference := &openapi.ResourceReference{Schema: schemaRef}
+	if paths := g.resources[target]; len(paths) > 0 {
+		reference.Path = paths[0]
+	}
+	property.SetReference(reference)
+
+	// the Id of description is the last one translateSchema adds
+	if strings.HasSuffix(property.Description, "of "+target) {
+		property.Description = fmt.Sprintf("%s[%s](%s)", strings.TrimSuffix(property.Description, target), target, schemaRef)
+	}
+	return property
+}
+
+// responseLinks - links to the resources referenced by the resource a
+// response returns. Path parameters other than the id of the referenced
+// resource are taken from the request, references to resources at paths
+// with other parameters aren't linked.
+func (g *Generator) responseLinks(responsePath string, response openapi.Response) map[string]openapi.Link {
+	media, ok := response.Content["application/json"]
+	if !ok || media.Schema == nil || media.Schema.Ref == "" {
+		return nil
+	}
+	schema := g.swagger.Components.Schemas[strings.TrimPrefix(media.Schema.Ref, "#/components/schemas/")]
+	properties := flatProperties(g.swagger.Components.Schemas, schema, map[string]bool{})
+
+	requestParams := make(map[string]bool)
+	for _, match := range pathParameter.FindAllStringSubmatch(responsePath, -1) {
+		requestParams[match[1]] = true
+	}
+
+	links := make(map[string]openapi.Link)
+	for field, property := range properties {
+		reference := property.Reference()
+		if reference == nil || property.Type != "string" {
+			continue
+		}
+		target := strings.TrimPrefix(reference.Schema, "#/components/schemas/")
+		idParam := fmt.Sprintf("%sId", target)
+		for _, resourcePath := range g.resources[target] {
+			parameters := make(map[string]string)
+			for _, match := range pathParameter.FindAllStringSubmatch(resourcePath, -1) {
+				switch {
+				case match[1] == idParam:
+					parameters[idParam] = fmt.Sprintf("$response.body#/%s", field)
+				case requestParams[match[1]]:
+					parameters[match[1]] = fmt.Sprintf("$request.path.%s", match[1])
+				}
+			}
+			if len(parameters) != len(pathParameter.FindAllString(resourcePath, -1)) || parameters[idParam] == "" {
+				continue
+			}
+			links[field] = openapi.Link{
+				OperationRef: fmt.Sprintf("#/paths/%s/get", pointerEscape(resourcePath)),
+				Parameters:   parameters,
+				Description:  fmt.Sprintf("The `%s` of `%s`", target, field),
+			}
+			break
+		}
+	}
+	return links
+}
+
+// pointerEscape - s as a JSON pointer token, / as ~1 and ~ as ~0
+func pointerEscape(s string) string {
+	return strings.Replace(strings.Replace(s, "~", "~0", -1), "/", "~1", -1)
+}
+
+func sortedPaths(m map[string]openapi.PathItem) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func TestGenerateReferences(t *testing.T) {
+	swagger, _ := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	node := swagger.Components.Schemas["node"]
+	clusterID := ownSchema(&node).Properties["clusterId"]
+	expected := &openapi.ResourceReference{Schema: "#/components/schemas/cluster", Path: "/clusters/{clusterId}"}
+	if reference := clusterID.Reference(); !reflect.DeepEqual(reference, expected) {
+		t.Errorf("expected node.clusterId to reference %v, got %v", expected, reference)
+	}
+	if clusterID.Description != "Allowed in Methods: `POST`; Id of [cluster](#/components/schemas/cluster)" {
+		t.Errorf("expected the description to link to cluster, got %q", clusterID.Description)
+	}
+
+	// a reference to a schema of another API root is by its link
+	workload := swagger.Components.Schemas["workload"]
+	namespaceID := ownSchema(&workload).Properties["namespaceId"]
+	expected = &openapi.ResourceReference{Schema: "#/components/schemas/namespace", Path: "/cluster/{clusterId}/namespaces/{namespaceId}"}
+	if reference := namespaceID.Reference(); !reflect.DeepEqual(reference, expected) {
+		t.Errorf("expected workload.namespaceId to reference %v, got %v", expected, reference)
+	}
+
+	links := swagger.Paths["/clusters/{clusterId}/nodes/{nodeId}"].Get.Responses["200"].Links
+	link, ok := links["clusterId"]
+	if !ok || link.OperationRef != "#/paths/~1clusters~1{clusterId}/get" || link.Parameters["clusterId"] != "$response.body#/clusterId" {
+		t.Errorf("expected a link to the cluster of a node, got %+v", links)
+	}
+	if links := swagger.Paths["/project/{projectId}/workloads/{workloadId}"].Get.Responses["200"].Links; len(links) != 0 {
+		t.Errorf("expected no link to a namespace without the clusterId, got %+v", links)
+	}
+	if links := swagger.Paths["/nodes"].Get.Responses["200"].Links; len(links) != 0 {
+		t.Errorf("expected no links on a list of nodes, got %+v", links)
+	}
+}
+
+// overlays and base files go through YAML, the references read back still link
+func TestReferencesAfterYAML(t *testing.T) {
+	swagger, _ := generateFake(t, DefaultMaxDepth, defaultRules(t))
+	data, err := yaml.Marshal(swagger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := &openapi.OpenAPI{}
+	if err := yaml.Unmarshal(data, read); err != nil {
+		t.Fatal(err)
+	}
+
+	node := read.Components.Schemas["node"]
+	expected := &openapi.ResourceReference{Schema: "#/components/schemas/cluster", Path: "/clusters/{clusterId}"}
+	if reference := ownSchema(&node).Properties["clusterId"].Reference(); !reflect.DeepEqual(reference, expected) {
+		t.Errorf("expected node.clusterId to still reference %v, got %v", expected, reference)
+	}
+
+	g := &Generator{swagger: read, resources: map[string][]string{"cluster": {"/clusters/{clusterId}"}}}
+	nodePath := "/clusters/{clusterId}/nodes/{nodeId}"
+	links := g.responseLinks(nodePath, read.Paths[nodePath].Get.Responses["200"])
+	if link, ok := links["clusterId"]; !ok || link.Parameters["clusterId"] != "$response.body#/clusterId" {
+		t.Errorf("expected a link to the cluster of a node, got %+v", links)
+	}
+}
+
+func TestPointerEscape(t *testing.T) {
+	if escaped := pointerEscape("/clusters/{clusterId}~"); escaped != "~1clusters~1{clusterId}~0" {
+		t.Errorf("expected / and ~ escaped, got %s", escaped)
+	}
+}
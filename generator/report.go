@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Report - what was crawled, what was left out and why
+type Report struct {
+	Rules       *Rules          `json:"rules,omitempty"`
+	Roots       []Root          `json:"roots"`
+	Collections []ReportEntry   `json:"collections"`
+	Skipped     []ReportEntry   `json:"skipped"`
+	Failed      []ReportEntry   `json:"failed"`
+	Pruned      []Edge          `json:"pruned"`
+	Missing     []MissingSchema `json:"missing"`
+}
+
+// ReportEntry - a single collection in the crawl report
+type ReportEntry struct {
+	Collection string `json:"collection"`
+	Path       string `json:"path"`
+	Link       string `json:"link"`
+	Schema     string `json:"schema,omitempty"`
+	Rule       string `json:"rule,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// MissingSchema - a schema that is referenced but couldn't be found
+type MissingSchema struct {
+	Schema       string `json:"schema"`
+	Root         string `json:"root"`
+	ReferencedBy string `json:"referencedBy,omitempty"`
+	Reason       string `json:"reason"`
+}
+
+func newReport(rules *Rules) *Report {
+	return &Report{
+		Rules:       rules,
+		Roots:       make([]Root, 0),
+		Collections: make([]ReportEntry, 0),
+		Skipped:     make([]ReportEntry, 0),
+		Failed:      make([]ReportEntry, 0),
+		Pruned:      make([]Edge, 0),
+		Missing:     make([]MissingSchema, 0),
+	}
+}
+
+func newReportEntry(target crawlTarget, schema string) ReportEntry {
+	return ReportEntry{
+		Collection: target.Col,
+		Path:       target.Base + target.Col,
+		Link:       target.Link,
+		Schema:     schema,
+	}
+}
+
+func (r *Report) crawled(target crawlTarget, schema string) {
+	r.Collections = append(r.Collections, newReportEntry(target, schema))
+}
+
+func (r *Report) skipped(target crawlTarget, schema string, rule *Rule, reason string) {
+	entry := newReportEntry(target, schema)
+	entry.Reason = reason
+	if rule != nil {
+		entry.Rule = rule.String()
+		entry.Reason = rule.Reason
+	}
+	r.Skipped = append(r.Skipped, entry)
+}
+
+func (r *Report) failed(target crawlTarget, err error) {
+	entry := newReportEntry(target, "")
+	entry.Reason = err.Error()
+	r.Failed = append(r.Failed, entry)
+}
+
+func (r *Report) missing(schema string, root string, referencedBy string, reason string) {
+	for _, m := range r.Missing {
+		if m.Schema == schema && m.Root == root && m.ReferencedBy == referencedBy {
+			return
+		}
+	}
+	r.Missing = append(r.Missing, MissingSchema{
+		Schema:       schema,
+		Root:         root,
+		ReferencedBy: referencedBy,
+		Reason:       reason,
+	})
+}
+
+func (r *Report) write(file string) error {
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, out, 0644)
+}
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"fmt"
+	neturl "net/url"
+	"path"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+const (
+	managementRoot = "management"
+	publicRoot     = "public"
+)
+
+// scope - an API root below the management API, served for each resource of
+// a management collection, e.g. /v3/cluster/<clusterId> for every cluster
+type scope struct {
+	Name       string
+	Collection string
+	Param      string
+}
+
+var scopes = []scope{
+	{Name: "cluster", Collection: "clusters", Param: "clusterId"},
+	{Name: "project", Collection: "projects", Param: "projectId"},
+}
+
+// Root - an API root crawled with its own schemas
+type Root struct {
+	// Name - management, public, cluster or project
+	Name string `json:"name"`
+	// Path - prefix of the paths documented for the root, e.g. /cluster/{clusterId}/.
+	// Public paths are relative to the server root rather than the API root.
+	Path string `json:"path"`
+	// Link - the API root crawled, scoped roots use the first resource of their collection
+	Link string `json:"link,omitempty"`
+	// Param - path parameter for the scope, empty for the management and public APIs
+	Param string `json:"param,omitempty"`
+	// Public - the API is served without authentication
+	Public bool `json:"public,omitempty"`
+	// Reason - why the root wasn't crawled
+	Reason string `json:"reason,omitempty"`
+}
+
+// roots - the management API, the public API next to it and a root for every scope.
+// Paths are documented the same for every cluster or project, so only the
+// first resource of each scope's collection is crawled.
+func (g *Generator) roots() []Root {
+	roots := []Root{{
+		Name: managementRoot,
+		Path: "/",
+		Link: g.opts.URL,
+	}, {
+		Name:   publicRoot,
+		Path:   fmt.Sprintf("/%s/", path.Base(publicLink(g.opts.URL))),
+		Link:   publicLink(g.opts.URL),
+		Public: true,
+	}}
+
+	for _, s := range scopes {
+		root := Root{
+			Name:  s.Name,
+			Path:  fmt.Sprintf("/%s/{%s}/", s.Name, s.Param),
+			Param: s.Param,
+		}
+		collection, err := g.source.Collection(fmt.Sprintf("%s/%s", g.opts.URL, s.Collection))
+		switch {
+		case err != nil:
+			root.Reason = fmt.Sprintf("Failed to list %s - %v", s.Collection, err)
+		case len(collection.Data) == 0:
+			root.Reason = fmt.Sprintf("No %s to crawl the %s API with", s.Collection, s.Name)
+		default:
+			root.Link = fmt.Sprintf("%s/%s/%s", g.opts.URL, s.Name, collection.Data[0].ID)
+		}
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// pushRoot - queue the collections of root, documented under root.Path
+func (g *Generator) pushRoot(root Root, collections map[string]string) {
+	if root.Param != "" {
+		g.createPathParameter(root.Param)
+	}
+	for _, col := range sortedKeys(collections) {
+		g.pushLink(root.Path, col, collections[col], root.Path, 0)
+	}
+}
+
+// markPublic - document the paths of a public root on the server root,
+// without authentication
+func (g *Generator) markPublic(root Root) {
+	servers := make([]openapi.Server, 0, len(g.swagger.Servers))
+	apiPath := "/" + path.Base(strings.TrimSuffix(g.opts.URL, "/"))
+	for _, server := range g.swagger.Servers {
+		server.URL = strings.TrimSuffix(strings.TrimSuffix(server.URL, "/"), apiPath)
+		servers = append(servers, server)
+	}
+
+	for name, pathItem := range g.swagger.Paths {
+		if !strings.HasPrefix(name, root.Path) {
+			continue
+		}
+		pathItem.Servers = servers
+		for _, op := range pathItem.Operations() {
+			op.Security = &[]map[string][]string{}
+		}
+		g.swagger.Paths[name] = pathItem
+	}
+}
+
+// publicLink - the unauthenticated API next to the API at url, /v3 -> /v3-public
+func publicLink(url string) string {
+	return strings.TrimSuffix(url, "/") + "-public"
+}
+
+// schemaRoot - the API root a link is in, its schemas are at <root>/schemas.
+// The URL for the management API, <URL>/cluster/<id> or <URL>/project/<id>
+// for a scope and the public link for the public API.
+func (g *Generator) schemaRoot(link string) string {
+	public := publicLink(g.opts.URL)
+	if link == public || strings.HasPrefix(link, public+"/") {
+		return public
+	}
+
+	u, err := neturl.Parse(link)
+	if err != nil {
+		return g.opts.URL
+	}
+	base, err := neturl.Parse(g.opts.URL)
+	if err != nil {
+		return g.opts.URL
+	}
+
+	prefix := strings.TrimSuffix(base.Path, "/") + "/"
+	if !strings.HasPrefix(u.Path, prefix) {
+		return g.opts.URL
+	}
+	segments := strings.Split(strings.TrimPrefix(path.Clean(u.Path), prefix), "/")
+	for _, s := range scopes {
+		if len(segments) > 2 && segments[0] == s.Name {
+			return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(g.opts.URL, "/"), s.Name, segments[1])
+		}
+	}
+	return g.opts.URL
+}
@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func TestGenerateScopedRoots(t *testing.T) {
+	srv := newFakeRancher(t)
+	defer srv.Close()
+	srv.SetSchemaPageSize(3)
+
+	swagger, report, err := New(Options{
+		URL:      srv.URL + "/v3",
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPaths(t, swagger, []string{
+		"/cluster/{clusterId}/namespaces",
+		"/cluster/{clusterId}/namespaces/{namespaceId}",
+		"/project/{projectId}/workloads",
+		"/project/{projectId}/workloads/{workloadId}",
+		"/project/{projectId}/pods",
+		"/project/{projectId}/pods/{podId}",
+	}, []string{
+		"/clusters/{clusterId}/namespaces",
+		"/projects/{projectId}/workloads",
+	})
+
+	workloads := swagger.Paths["/project/{projectId}/workloads"]
+	if len(workloads.Parameters) != 1 || workloads.Parameters[0].Ref != "#/components/parameters/projectId" {
+		t.Errorf("expected projectId parameter on project collection, got %+v", workloads.Parameters)
+	}
+	if _, ok := swagger.Components.Parameters["projectId"]; !ok {
+		t.Error("missing projectId parameter")
+	}
+	if workloads.Post == nil {
+		t.Error("expected POST on /project/{projectId}/workloads")
+	}
+	if workload := swagger.Paths["/project/{projectId}/workloads/{workloadId}"]; workload.Post == nil || workload.Post.Actions() == nil {
+		t.Errorf("expected the redeploy action on a workload, got %+v", workload.Post)
+	} else if _, ok := workload.Post.Actions()["redeploy"]; !ok {
+		t.Errorf("expected the redeploy action on a workload, got %+v", workload.Post.Actions())
+	}
+	if pods := swagger.Paths["/project/{projectId}/pods"]; pods.Get == nil || pods.Post != nil {
+		t.Errorf("expected read only pods collection, got %+v", pods)
+	}
+	namespace := swagger.Components.Schemas["namespace"]
+	if ref := ownSchema(&namespace).Properties["status"].Ref; ref != "#/components/schemas/namespaceStatus" {
+		t.Errorf("expected namespace.status to reference namespaceStatus, got %q", ref)
+	}
+	for _, name := range []string{"namespace", "namespaceStatus", "workload", "pod"} {
+		if _, ok := swagger.Components.Schemas[name]; !ok {
+			t.Errorf("missing schema %s", name)
+		}
+	}
+
+	roots := make(map[string]Root)
+	for _, root := range report.Roots {
+		roots[root.Name] = root
+	}
+	for name, link := range map[string]string{
+		"management": "/v3",
+		"cluster":    "/v3/cluster/c-1",
+		"project":    "/v3/project/c-1:p-1",
+	} {
+		if roots[name].Link != srv.URL+link || roots[name].Reason != "" {
+			t.Errorf("expected the %s API to be crawled from %s, got %+v", name, link, roots[name])
+		}
+	}
+	if !hasEdge(report.Pruned, "/clusters/{clusterId}/", "namespaces") {
+		t.Errorf("expected namespaces link from the cluster to be pruned, got %+v", report.Pruned)
+	}
+	if len(report.Failed) != 0 || len(report.Missing) != 0 {
+		t.Errorf("expected no failures or missing schemas, got %+v %+v", report.Failed, report.Missing)
+	}
+
+	for _, request := range srv.Requests() {
+		if strings.Contains(request, "/schemas/") {
+			t.Errorf("expected every scope's schemas to come from its index, got request %s", request)
+		}
+	}
+}
+
+func TestGeneratePublicRoot(t *testing.T) {
+	swagger, report := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	assertPaths(t, swagger, []string{
+		"/v3-public/authProviders",
+		"/v3-public/localProviders",
+		"/v3-public/localProviders/{localProviderId}",
+	}, nil)
+
+	login := swagger.Paths["/v3-public/localProviders/{localProviderId}"]
+	if login.Post == nil || login.Post.Security == nil || len(*login.Post.Security) != 0 {
+		t.Fatalf("expected login with an empty security requirement, got %+v", login.Post)
+	}
+	if login.Post.RequestBody == nil || login.Post.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/basicLogin" {
+		t.Errorf("expected login taking basicLogin, got %+v", login.Post.RequestBody)
+	}
+	if login.Post.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/token" {
+		t.Errorf("expected login returning token, got %+v", login.Post.Responses)
+	}
+	if len(login.Servers) != 1 || login.Servers[0].URL != "https://{hostname}" || login.Servers[0].Variables["hostname"].Description == "" {
+		t.Errorf("expected public paths on the server root, got %+v", login.Servers)
+	}
+	if props := swagger.Components.Schemas["basicLogin"].Properties; props["password"].Format != "password" {
+		t.Errorf("expected basicLogin.password to be a password, got %+v", props)
+	}
+	if clusters := swagger.Paths["/clusters"]; clusters.Get.Security != nil || clusters.Servers != nil {
+		t.Errorf("expected management paths to keep the document security and servers, got %+v", clusters)
+	}
+	if samples := login.Post.CodeSamples(); len(samples) == 0 || strings.Contains(samples[0].Source, "Authorization") {
+		t.Errorf("expected unauthenticated code samples, got %+v", samples)
+	}
+
+	for _, root := range report.Roots {
+		if root.Name == "public" && (!root.Public || root.Reason != "" || !strings.HasSuffix(root.Link, "/v3-public")) {
+			t.Errorf("expected the public API to be crawled, got %+v", root)
+		}
+	}
+}
+
+func TestGenerateWithoutScopedResources(t *testing.T) {
+	srv := newFakeRancher(t)
+	defer srv.Close()
+
+	swagger, report, err := New(Options{
+		URL:      srv.URL + "/v3",
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+		Source:   &emptyProjects{Source: NewHTTPSource("", nil)},
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPaths(t, swagger, []string{"/cluster/{clusterId}/namespaces"}, []string{"/project/{projectId}/workloads"})
+	for _, root := range report.Roots {
+		if root.Name == "project" && !strings.Contains(root.Reason, "No projects") {
+			t.Errorf("expected the project API to be skipped without projects, got %+v", root)
+		}
+	}
+}
+
+// emptyProjects - a Source without any projects
+type emptyProjects struct {
+	Source
+}
+
+func (s *emptyProjects) Collection(link string) (*Collection, error) {
+	collection, err := s.Source.Collection(link)
+	if err == nil && strings.HasSuffix(link, "/projects") {
+		collection.Data = nil
+	}
+	return collection, err
+}
+
+func TestSchemaRoot(t *testing.T) {
+	url := "https://rancher.example.com/v3"
+	g := New(Options{URL: url})
+	for link, expected := range map[string]string{
+		url + "/clusters":                          url,
+		url + "/clusters/c-1/nodes":                url,
+		url + "/cluster/c-1/namespaces":            url + "/cluster/c-1",
+		url + "/project/c-1:p-1/workloads":         url + "/project/c-1:p-1",
+		url + "/project/c-1:p-1/workloads/w-1/":    url + "/project/c-1:p-1",
+		url + "/project":                           url,
+		url + "-public/localProviders":             url + "-public",
+		"https://other.example.com/v1/project/c-1": url,
+	} {
+		if root := g.schemaRoot(link); root != expected {
+			t.Errorf("%s: expected %q, got %q", link, expected, root)
+		}
+	}
+}
+
+func TestMarkPublic(t *testing.T) {
+	g := New(Options{URL: "https://rancher.example.com/v3"})
+	g.swagger = &openapi.OpenAPI{
+		Servers: []openapi.Server{{URL: "https://{hostname}/v3"}},
+		Paths: map[string]openapi.PathItem{
+			"/v3-public/localProviders/{localProviderId}": {
+				Get:   &openapi.Operation{},
+				Post:  &openapi.Operation{},
+				Patch: &openapi.Operation{},
+				Head:  &openapi.Operation{},
+			},
+			"/clusters": {Get: &openapi.Operation{}},
+		},
+	}
+	g.markPublic(Root{Name: "public", Path: "/v3-public", Public: true})
+
+	public := g.swagger.Paths["/v3-public/localProviders/{localProviderId}"]
+	for method, op := range public.Operations() {
+		if op.Security == nil || len(*op.Security) != 0 {
+			t.Errorf("expected %s of a public path without security, got %+v", method, op.Security)
+		}
+	}
+	if len(public.Servers) != 1 || public.Servers[0].URL != "https://{hostname}" {
+		t.Errorf("expected the public path on the server root, got %+v", public.Servers)
+	}
+	if clusters := g.swagger.Paths["/clusters"]; clusters.Get.Security != nil || clusters.Servers != nil {
+		t.Errorf("expected other paths to be left alone, got %+v", clusters)
+	}
+}
@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Rules - include/exclude rules deciding which collections are documented
+// Exclude rules always win. When there are include rules a root collection
+// has to match one of them, sub collections of an included collection are
+// included unless they are excluded.
+type Rules struct {
+	Include []Rule `yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude []Rule `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// Rule - patterns matched against a collection.
+// Every pattern set on the rule has to match. Patterns are globs, `*` matches
+// within a path segment and `**` across segments, unless Regex is set.
+type Rule struct {
+	Collection string `yaml:"collection,omitempty" json:"collection,omitempty"`
+	Path       string `yaml:"path,omitempty" json:"path,omitempty"`
+	Schema     string `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Regex      bool   `yaml:"regex,omitempty" json:"regex,omitempty"`
+	Reason     string `yaml:"reason,omitempty" json:"reason,omitempty"`
+
+	collection *regexp.Regexp
+	path       *regexp.Regexp
+	schema     *regexp.Regexp
+}
+
+// ruleTarget - what rules are matched against.
+// Schema is empty until the collection has been fetched.
+type ruleTarget struct {
+	Collection string
+	Path       string
+	Schema     string
+}
+
+// LoadRules - read and compile a YAML rules file
+func LoadRules(file string) (*Rules, error) {
+	rules := &Rules{}
+	yamlRules, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(yamlRules, rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules, rules.Compile()
+}
+
+// Compile - compile the patterns, call after changing the rules
+func (r *Rules) Compile() error {
+	for _, rules := range [][]Rule{r.Include, r.Exclude} {
+		for i := range rules {
+			if err := rules[i].compile(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// excluded - the first exclude rule matching the target
+// Rules on schema IDs can't match until the schema is known.
+func (r *Rules) excluded(t ruleTarget) *Rule {
+	for i, rule := range r.Exclude {
+		if rule.matches(t) {
+			return &r.Exclude[i]
+		}
+	}
+	return nil
+}
+
+// included - whether a root collection matches the include rules
+// With final false the schema is not known yet, so rules on schema IDs
+// are given the benefit of the doubt.
+func (r *Rules) included(t ruleTarget, final bool) bool {
+	if len(r.Include) == 0 {
+		return true
+	}
+	for _, rule := range r.Include {
+		if rule.matches(t) {
+			return true
+		}
+		if !final && rule.schema != nil && rule.matchesIgnoringSchema(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) compile() error {
+	var err error
+	if r.collection, err = r.pattern(r.Collection); err != nil {
+		return fmt.Errorf("Invalid collection pattern %s - %v", r.Collection, err)
+	}
+	if r.path, err = r.pattern(r.Path); err != nil {
+		return fmt.Errorf("Invalid path pattern %s - %v", r.Path, err)
+	}
+	if r.schema, err = r.pattern(r.Schema); err != nil {
+		return fmt.Errorf("Invalid schema pattern %s - %v", r.Schema, err)
+	}
+	if r.collection == nil && r.path == nil && r.schema == nil {
+		return fmt.Errorf("Rule has no patterns: %s", r.Reason)
+	}
+	return nil
+}
+
+func (r *Rule) pattern(p string) (*regexp.Regexp, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if r.Regex {
+		return regexp.Compile(p)
+	}
+	return regexp.Compile(globToRegexp(p))
+}
+
+func (r *Rule) matches(t ruleTarget) bool {
+	if r.schema != nil && (t.Schema == "" || !r.schema.MatchString(t.Schema)) {
+		return false
+	}
+	return r.matchesIgnoringSchema(t)
+}
+
+func (r *Rule) matchesIgnoringSchema(t ruleTarget) bool {
+	if r.collection != nil && !r.collection.MatchString(t.Collection) {
+		return false
+	}
+	if r.path != nil && !r.path.MatchString(t.Path) {
+		return false
+	}
+	return true
+}
+
+// String - description of the rule for logs and the crawl report
+func (r *Rule) String() string {
+	patterns := make([]string, 0)
+	if r.Collection != "" {
+		patterns = append(patterns, fmt.Sprintf("collection=%s", r.Collection))
+	}
+	if r.Path != "" {
+		patterns = append(patterns, fmt.Sprintf("path=%s", r.Path))
+	}
+	if r.Schema != "" {
+		patterns = append(patterns, fmt.Sprintf("schema=%s", r.Schema))
+	}
+	return strings.Join(patterns, " ")
+}
+
+// globToRegexp - `**` matches anything, `*` and `?` don't cross a `/`.
+// Braces are literal so path templates like /clusters/{clusterId} match themselves.
+func globToRegexp(glob string) string {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				re.WriteString(".*")
+				i++
+			} else {
+				re.WriteString("[^/]*")
+			}
+		case '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	return re.String()
+}
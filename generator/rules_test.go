@@ -0,0 +1,47 @@
+package generator
+
+import "testing"
+
+func TestRuleMatches(t *testing.T) {
+	target := ruleTarget{
+		Collection: "nodes",
+		Path:       "/clusters/{clusterId}/nodes",
+		Schema:     "node",
+	}
+	for _, test := range []struct {
+		rule    Rule
+		matches bool
+	}{
+		{Rule{Collection: "nodes"}, true},
+		{Rule{Collection: "node?"}, true},
+		{Rule{Collection: "clusters"}, false},
+		{Rule{Path: "/clusters/*"}, false},
+		{Rule{Path: "/clusters/**"}, true},
+		{Rule{Path: "/clusters/{clusterId}/nodes"}, true},
+		{Rule{Path: "/clusters/{clusterId}/*"}, true},
+		{Rule{Collection: "nodes", Schema: "project"}, false},
+		{Rule{Schema: "^no", Regex: true}, true},
+		{Rule{Path: "^/nodes", Regex: true}, false},
+	} {
+		if err := test.rule.compile(); err != nil {
+			t.Fatal(err)
+		}
+		if actual := test.rule.matches(target); actual != test.matches {
+			t.Errorf("%s: expected %v, got %v", test.rule.String(), test.matches, actual)
+		}
+	}
+}
+
+func TestRulesIncludedBeforeSchemaIsKnown(t *testing.T) {
+	rules := &Rules{Include: []Rule{{Schema: "cluster"}}}
+	if err := rules.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	target := ruleTarget{Collection: "clusters", Path: "/clusters"}
+	if !rules.included(target, false) {
+		t.Error("schema rules should not exclude before the schema is known")
+	}
+	if rules.included(target, true) {
+		t.Error("schema rules should exclude once the schema is known")
+	}
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"fmt"
+
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// getSchema - a schema from the index of the API root at root.
+// The whole schemas collection of a root is loaded on first use, so every
+// reference is resolved without another request and a schema that isn't in
+// the collection is reported as missing.
+func (g *Generator) getSchema(root string, id string, referencedBy string) (norman.Schema, error) {
+	index, ok := g.schemas[root]
+	if !ok {
+		index = g.loadSchemas(root)
+		g.schemas[root] = index
+	}
+
+	if index == nil {
+		// No schemas collection for this root, fetch the schema on its own
+		schema, err := g.source.Schema(fmt.Sprintf("%s/schemas/%s", root, id))
+		if err != nil {
+			g.report.missing(id, root, referencedBy, err.Error())
+		}
+		return schema, err
+	}
+
+	schema, ok := index[id]
+	if !ok {
+		err := fmt.Errorf("Schema %s is not in %s/schemas", id, root)
+		g.report.missing(id, root, referencedBy, err.Error())
+		return schema, err
+	}
+	return schema, nil
+}
+
+// loadSchemas - index the schemas collection of root by ID, along with the
+// dynamic schemas of active drivers, nil if it can't be read
+func (g *Generator) loadSchemas(root string) map[string]norman.Schema {
+	schemas, err := g.source.Schemas(root)
+	if err != nil {
+		log.Warnf("Failed to load %s/schemas, fetching schemas one by one - %v", root, err)
+		return nil
+	}
+
+	index := make(map[string]norman.Schema, len(schemas))
+	for _, schema := range schemas {
+		index[schema.ID] = schema
+	}
+	log.Debugf("Loaded %d schemas from %s/schemas", len(index), root)
+	g.loadDynamicSchemas(root, index)
+	return index
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateLoadsSchemasInBulk(t *testing.T) {
+	srv := newFakeRancher(t)
+	defer srv.Close()
+	srv.SetSchemaPageSize(3)
+
+	swagger, report, err := New(Options{
+		URL:      srv.URL + "/v3",
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages := 0
+	for _, request := range srv.Requests() {
+		switch {
+		case strings.HasPrefix(request, "/v3/schemas/"):
+			t.Errorf("expected schemas to come from the index, got request %s", request)
+		case strings.HasPrefix(request, "/v3/schemas"):
+			pages++
+		}
+	}
+	if pages != 5 {
+		t.Errorf("expected the 13 schemas in 5 pages, got %d requests", pages)
+	}
+	for _, name := range []string{"cluster", "clusterCondition", "rancherKubernetesEngineConfig", "generateKubeConfigOutput", "rotateCertificateInput", "node", "project"} {
+		if _, ok := swagger.Components.Schemas[name]; !ok {
+			t.Errorf("missing schema %s", name)
+		}
+	}
+	if len(report.Missing) != 0 {
+		t.Errorf("expected no missing schemas, got %+v", report.Missing)
+	}
+}
+
+func TestGenerateReportsMissingSchemas(t *testing.T) {
+	srv := newFakeRancher(t)
+	defer srv.Close()
+	srv.RemoveSchema("/v3", "clusterCondition")
+	srv.RemoveSchema("/v3", "node")
+
+	swagger, report, err := New(Options{
+		URL:      srv.URL + "/v3",
+		Rules:    defaultRules(t),
+		MaxDepth: DefaultMaxDepth,
+		Base:     "../data/base.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPaths(t, swagger, []string{"/clusters", "/projects"}, []string{"/nodes"})
+	missing := make(map[string]string)
+	for _, m := range report.Missing {
+		missing[m.Schema+" <- "+m.ReferencedBy] = m.Reason
+		if m.Root != srv.URL+"/v3" {
+			t.Errorf("expected %s to be missing from %s/v3, got %s", m.Schema, srv.URL, m.Root)
+		}
+	}
+	for _, expected := range []string{"clusterCondition <- cluster", "node <- /nodes"} {
+		if _, ok := missing[expected]; !ok {
+			t.Errorf("expected %s to be reported missing, got %+v", expected, report.Missing)
+		}
+	}
+	if !strings.Contains(missing["node <- /nodes"], "is not in") {
+		t.Errorf("expected an explicit reason, got %q", missing["node <- /nodes"])
+	}
+}
@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// Collections -
+type Collections struct {
+	Links map[string]string `json:"links"`
+}
+
+// Collection -
+type Collection struct {
+	*norman.Collection
+	Data []norman.Resource `json:"data"`
+}
+
+// schemaList - a page of the schemas collection
+type schemaList struct {
+	Data       []norman.Schema    `json:"data"`
+	Pagination *norman.Pagination `json:"pagination,omitempty"`
+}
+
+// Source - where the API root, collections and schemas are read from.
+// Everything is addressed by the links found in the API, so the translation
+// works the same against a live server or a dump of one.
+type Source interface {
+	// Collections - links to the collections in the API root at link
+	Collections(link string) (map[string]string, error)
+	// Collection - the collection at link
+	Collection(link string) (*Collection, error)
+	// Schema - the schema at link, <root>/schemas/<id>
+	Schema(link string) (norman.Schema, error)
+	// Schemas - every schema in the API root at link, across all pages
+	Schemas(link string) ([]norman.Schema, error)
+	// DynamicSchemas - the dynamic schemas of active drivers in the
+	// dynamicSchemas collection at link, across all pages
+	DynamicSchemas(link string) ([]DynamicSchema, error)
+	// ContentType - media type of the document at link, for links that
+	// aren't collections, e.g. application/yaml for a yaml export
+	ContentType(link string) (string, error)
+}
+
+// getter - reads the JSON document at a link
+type getter func(link string) ([]byte, error)
+
+// HTTPSource - a live Rancher API
+type HTTPSource struct {
+	token  string
+	client *http.Client
+}
+
+// NewHTTPSource - read the API with a bearer token, client defaults to one skipping TLS verification
+func NewHTTPSource(token string, client *http.Client) *HTTPSource {
+	if client == nil {
+		client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+	}
+	return &HTTPSource{
+		token:  token,
+		client: client,
+	}
+}
+
+// Collections - links in the API root
+func (s *HTTPSource) Collections(link string) (map[string]string, error) {
+	return getCollections(s.Get, link)
+}
+
+// Collection - GET a collection
+func (s *HTTPSource) Collection(link string) (*Collection, error) {
+	return getCollection(s.Get, link)
+}
+
+// Schema - GET a schema
+func (s *HTTPSource) Schema(link string) (norman.Schema, error) {
+	return getSchema(s.Get, link)
+}
+
+// Schemas - GET the schemas collection
+func (s *HTTPSource) Schemas(link string) ([]norman.Schema, error) {
+	return getSchemas(s.Get, link)
+}
+
+// DynamicSchemas - GET the dynamicSchemas collection
+func (s *HTTPSource) DynamicSchemas(link string) ([]DynamicSchema, error) {
+	return getDynamicSchemas(s.Get, link)
+}
+
+// ContentType - GET link and return the media type of the response, without reading it
+func (s *HTTPSource) ContentType(link string) (string, error) {
+	resp, err := s.get(link, "*/*")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return "", fmt.Errorf("GET %s returned an invalid Content-Type - %v", link, err)
+	}
+	return mediaType, nil
+}
+
+// Get - GET the JSON document at url
+func (s *HTTPSource) Get(url string) ([]byte, error) {
+	resp, err := s.get(url, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	jsonBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonBody, nil
+}
+
+// get - GET url accepting accept, the response has a 2xx status
+func (s *HTTPSource) get(url string, accept string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", fmt.Sprint("Bearer ", s.token))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	goodStatus := regexp.MustCompile("^2\\d\\d")
+	if !goodStatus.MatchString(resp.Status) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s returned %s", req.Method, req.URL, resp.Status)
+	}
+	return resp, nil
+}
+
+func getSchema(get getter, link string) (norman.Schema, error) {
+	schema := norman.Schema{}
+
+	schemaResponse, err := get(link)
+	if err != nil {
+		return schema, err
+	}
+
+	err = json.Unmarshal(schemaResponse, &schema)
+	if err != nil {
+		log.Error(string(schemaResponse))
+		log.Error(err)
+		return schema, err
+	}
+
+	return schema, nil
+}
+
+// getSchemas - the schemas collection, following the next page links
+func getSchemas(get getter, link string) ([]norman.Schema, error) {
+	schemas := make([]norman.Schema, 0)
+	seen := make(map[string]bool)
+	for next := link + "/schemas"; next != ""; {
+		if seen[next] {
+			return nil, fmt.Errorf("Pagination of %s/schemas loops back to %s", link, next)
+		}
+		seen[next] = true
+
+		response, err := get(next)
+		if err != nil {
+			return nil, err
+		}
+		list := &schemaList{}
+		err = json.Unmarshal(response, list)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, list.Data...)
+
+		next = ""
+		if list.Pagination != nil {
+			next = list.Pagination.Next
+		}
+	}
+	return schemas, nil
+}
+
+func getCollections(get getter, link string) (map[string]string, error) {
+	collectionsResponse, err := get(link)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := Collections{}
+	err = json.Unmarshal(collectionsResponse, &collections)
+	if err != nil {
+		return nil, err
+	}
+
+	return collections.Links, nil
+}
+
+func getCollection(get getter, link string) (*Collection, error) {
+	collectionResponse, err := get(link)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &Collection{}
+	err = json.Unmarshal(collectionResponse, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,572 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// translateSchema - add the schema and every schema it references to the
+// components, references are resolved in the API root at schemaRoot.
+func (g *Generator) translateSchema(rancherSchema norman.Schema, schemaRoot string) {
+	properties := make(map[string]openapi.Schema)
+	required := make([]string, 0)
+	name := rancherSchema.ID
+	resourceFields := rancherSchema.ResourceFields
+
+	// Skip Schema if it already exists
+	_, ok := g.swagger.Components.Schemas[name]
+	if ok {
+		log.Debug(name, " Schema Already Exists")
+		return
+	}
+
+	// Required
+	for resourceName, resourceValue := range resourceFields {
+		if resourceValue.Required {
+			required = append(required, resourceName)
+		}
+	}
+	// Properties
+	for resourceName, resourceValue := range resourceFields {
+		desc := make([]string, 0)
+
+		p := &openapi.Schema{
+			Maximum:    Number(resourceValue.Max),
+			MaxLength:  resourceValue.MaxLength,
+			Minimum:    Number(resourceValue.Min),
+			MinLength:  resourceValue.MinLength,
+			Nullable:   resourceValue.Nullable,
+			Extensions: fieldExtensions(resourceValue),
+		}
+
+		usage := ""
+		if resourceValue.Create == false && resourceValue.Update == false {
+			p.ReadOnly = true
+		}
+		if resourceValue.Update || resourceValue.Create {
+			usage = fmt.Sprint("Allowed in Methods:")
+		}
+		if resourceValue.Create {
+			usage = fmt.Sprint(usage, " `POST`")
+		}
+		if resourceValue.Update {
+			usage = fmt.Sprint(usage, " `PUT`")
+		}
+		if usage != "" {
+			desc = append(desc, usage)
+		}
+
+		// Populate existing Description
+		if resourceValue.Description != "" {
+			desc = append(desc, resourceValue.Description)
+		}
+
+		// remap types to valid types for openapi
+		isValid := regexp.MustCompile("^(string|boolean|object|array)$")
+		isIntOrString := regexp.MustCompile("^intOrString$")
+		isArrayString := regexp.MustCompile("^array\\[string\\]$")
+		isArrayInt := regexp.MustCompile("^array\\[int\\]$")
+		isArrayEnum := regexp.MustCompile("^array\\[enum\\]$")
+		isRefArray := regexp.MustCompile("^array\\[(\\w+)\\]$")
+		isMapString := regexp.MustCompile("^map\\[string\\]$")
+		isMapBase64 := regexp.MustCompile("^map\\[base64\\]$")
+		isRefMap := regexp.MustCompile("^map\\[(\\w+)\\]$")
+		isRefID := regexp.MustCompile("^reference\\[([a-zA-Z0-9/]+)\\]$")
+		isArrayRefID := regexp.MustCompile("^array\\[reference\\[([a-zA-Z0-9/]+)\\]\\]$")
+		isEnum := regexp.MustCompile("^enum$")
+		isDate := regexp.MustCompile("^date$")
+		isPassword := regexp.MustCompile("^password$")
+		isInt := regexp.MustCompile("^int$")
+		isFloat := regexp.MustCompile("^float$")
+		isArrayFloat := regexp.MustCompile("^array\\[float\\]$")
+		isBase64 := regexp.MustCompile("^base64$")
+
+		dns, isDNS := dnsTypes[resourceValue.Type]
+
+		switch {
+		case isDNS:
+			p.Type = "string"
+			if p.MaxLength == nil {
+				p.MaxLength = &dns.maxLength
+			}
+			desc = append(desc, dns.description)
+
+		case isValid.MatchString(resourceValue.Type):
+			p.Type = resourceValue.Type
+
+		case isDate.MatchString(resourceValue.Type):
+			p.Type = "string"
+			p.Format = "date-time"
+
+		case isPassword.MatchString(resourceValue.Type):
+			p.Type = "string"
+			p.Format = "password"
+
+		case isIntOrString.MatchString(resourceValue.Type):
+			p.OneOf = []openapi.Schema{
+				openapi.Schema{
+					Type: "string",
+				},
+				openapi.Schema{
+					Type: "integer",
+				},
+			}
+
+		case isInt.MatchString(resourceValue.Type):
+			p.Type = "integer"
+
+		case isFloat.MatchString(resourceValue.Type):
+			p.Type = "number"
+			p.Format = "double"
+
+		case isBase64.MatchString(resourceValue.Type):
+			p.Type = "string"
+			desc = append(desc, "Base64 encoded string")
+
+		case isEnum.MatchString(resourceValue.Type):
+			p.Type = "string"
+
+		case isArrayString.MatchString(resourceValue.Type):
+			p.Type = "array"
+			desc = append(desc, "Array of Strings")
+			p.Items = &openapi.Schema{
+				Type: "string",
+			}
+
+		case isArrayInt.MatchString(resourceValue.Type):
+			p.Type = "array"
+			desc = append(desc, "Array of Integers")
+			p.Items = &openapi.Schema{
+				Type: "integer",
+			}
+
+		case isArrayFloat.MatchString(resourceValue.Type):
+			p.Type = "array"
+			desc = append(desc, "Array of Numbers")
+			p.Items = &openapi.Schema{
+				Type:   "number",
+				Format: "double",
+			}
+
+		case isArrayEnum.MatchString(resourceValue.Type):
+			p.Type = "array"
+			p.Items = &openapi.Schema{
+				Type: "string",
+				Enum: TypedValues(resourceValue.Options, "string"),
+			}
+			desc = append(desc, "Array of Valid Options")
+
+		case isRefArray.MatchString(resourceValue.Type):
+			// Will be a ref to other resource, resolve the other resource
+			refSchemaName := isRefArray.FindStringSubmatch(resourceValue.Type)[1]
+			subSchema, err := g.getSchema(schemaRoot, refSchemaName, name)
+			if err != nil {
+				log.Errorf("Failed to get Schema for root:%s name:%s ref:%s - %v", schemaRoot, name, refSchemaName, err)
+			} else {
+				// turtles all the way down
+				g.translateSchema(subSchema, schemaRoot)
+
+				p.Type = "array"
+				p.Items = &openapi.Schema{
+					Ref: fmt.Sprintf("#/components/schemas/%s", subSchema.ID),
+				}
+			}
+
+		case isMapString.MatchString(resourceValue.Type):
+			p.Type = "object"
+			example := make(map[string]string)
+			example["key"] = "value"
+			p.Example = example
+
+		case isMapBase64.MatchString(resourceValue.Type):
+			p.Type = "object"
+			example := make(map[string]string)
+			example["key"] = "base64 encoded string"
+			p.Example = example
+
+		case isRefMap.MatchString(resourceValue.Type):
+			refSchemaName := isRefMap.FindStringSubmatch(resourceValue.Type)[1]
+			subSchema, err := g.getSchema(schemaRoot, refSchemaName, name)
+			if err != nil {
+				log.Errorf("Failed to get Schema for root:%s name:%s ref:%s - %v", schemaRoot, name, refSchemaName, err)
+			} else {
+				// turtles all the way down
+				g.translateSchema(subSchema, schemaRoot)
+
+				p.Type = "object"
+				p.AdditionalProperties = &openapi.Schema{
+					Ref: fmt.Sprintf("#/components/schemas/%s", subSchema.ID),
+				}
+			}
+
+		case isRefID.MatchString(resourceValue.Type):
+			ref := isRefID.FindStringSubmatch(resourceValue.Type)[1]
+			p.Type = "string"
+			desc = append(desc, fmt.Sprintf("Id of %s", referencedSchema(ref)))
+
+		case isArrayRefID.MatchString(resourceValue.Type):
+			ref := isArrayRefID.FindStringSubmatch(resourceValue.Type)[1]
+			p.Type = "array"
+			p.Items = &openapi.Schema{
+				Type: "string",
+			}
+			desc = append(desc, fmt.Sprintf("Array of Ids of %s", referencedSchema(ref)))
+
+		default:
+			// Should be schema object
+			subSchema, err := g.getSchema(schemaRoot, resourceValue.Type, name)
+			if err != nil {
+				log.Errorf("Failed to get Schema for root:%s name:%s ref:%s - %v", schemaRoot, name, resourceValue.Type, err)
+			} else {
+
+				// turtles all the way down
+				g.translateSchema(subSchema, schemaRoot)
+
+				// the other fields are marshalled next to an allOf of the $ref
+				if subSchema.ID == "" {
+					log.Error("id is empty")
+				}
+				p.Ref = fmt.Sprintf("#/components/schemas/%s", subSchema.ID)
+			}
+		}
+
+		// options and defaults are typed like the property, e.g. numbers of an int
+		if !isArrayEnum.MatchString(resourceValue.Type) {
+			p.Enum = TypedValues(resourceValue.Options, p.Type)
+		}
+		p.Default = TypedValue(resourceValue.Default, p.Type)
+		if p.Type == "string" {
+			p.Pattern = fieldPattern(resourceValue)
+		}
+
+		p.Description = strings.Join(desc, "; ")
+		properties[resourceName] = *p
+	}
+
+	schemaObject := openapi.Schema{
+		Type:       "object",
+		Properties: properties,
+		Required:   required,
+	}
+	if base := g.baseSchema(rancherSchema, schemaRoot); base != "" {
+		schemaObject = g.extend(base, schemaObject)
+	}
+	schemaObject.Extensions = schemaExtensions(rancherSchema)
+	if driver, ok := g.drivers[schemaRoot][name]; ok {
+		schemaObject.SetDriver(&driver)
+	}
+
+	g.swagger.Components.Schemas[name] = schemaObject
+
+}
+
+// createResource - operation on a resource, item is the schema it is documented as
+func createResource(method string, resourceType string, item string) *openapi.Operation {
+	schema := &openapi.Schema{
+		Ref: fmt.Sprintf("#/components/schemas/%s", item),
+	}
+
+	content := make(map[string]openapi.MediaType)
+	content["application/json"] = openapi.MediaType{
+		Schema: schema,
+	}
+
+	resp := make(map[string]openapi.Response)
+	request := &openapi.RequestBody{}
+
+	if method == "GET" {
+		request = nil
+		resp["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns '%s' object.", resourceType),
+			Content:     content,
+		}
+	}
+	if method == "PUT" {
+		request.Description = fmt.Sprintf("Update `%s` object.", resourceType)
+		request.Content = content
+		resp["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns '%s' object.", resourceType),
+			Content:     content,
+		}
+	}
+	if method == "DELETE" {
+		request = nil
+		resp["204"] = openapi.Response{
+			Description: fmt.Sprint("Delete Successful"),
+		}
+	}
+
+	return &openapi.Operation{
+		Tags:        []string{resourceType},
+		Description: fmt.Sprintf("`%s` Resource", resourceType),
+		Responses:   resp,
+		RequestBody: request,
+	}
+}
+
+// createCollection - operation on a collection, item is the schema its resources are documented as
+func createCollection(method string, col string, collection *Collection, item string) *openapi.Operation {
+	schema := &openapi.Schema{
+		Ref: fmt.Sprintf("#/components/schemas/%s", item),
+	}
+
+	content := make(map[string]openapi.MediaType)
+	content["application/json"] = openapi.MediaType{
+		Schema: schema,
+	}
+
+	request := &openapi.RequestBody{}
+	resp := make(map[string]openapi.Response)
+
+	if method == "GET" {
+		request = nil
+		resp["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns list of '%s'", col),
+			Content:     content,
+		}
+	}
+	if method == "POST" {
+		request.Description = fmt.Sprintf("Create a new `%s` object.", collection.ResourceType)
+		request.Content = content
+		resp["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns new `%s` object.", collection.ResourceType),
+			Content:     content,
+		}
+	}
+
+	return &openapi.Operation{
+		Tags:        []string{collection.ResourceType},
+		Description: fmt.Sprintf("`%s` Collection", col),
+		Responses:   resp,
+		RequestBody: request,
+	}
+
+}
+
+// createActions - post with the actions of a collection or resource.
+// Norman selects an action with ?action=, on the path of the collection or
+// resource, so every action is the same POST with an action query parameter
+// and the request body and response are oneOf the inputs and outputs.
+// A collection POST that creates keeps its body first and action optional.
+func (g *Generator) createActions(post *openapi.Operation, actions map[string]norman.Action, resourceType string, schemaRoot string) *openapi.Operation {
+	names := sortedActions(actions)
+	if len(names) == 0 {
+		return post
+	}
+
+	op := post
+	var inputs, outputs []openapi.Schema
+	if op == nil {
+		op = &openapi.Operation{
+			Tags:        []string{resourceType},
+			Summary:     strings.Join(names, ", "),
+			Description: fmt.Sprintf("Actions on `%s`", resourceType),
+			Responses:   map[string]openapi.Response{"200": {Description: "Action Successful"}},
+		}
+	} else {
+		if op.RequestBody != nil {
+			inputs = appendSchema(inputs, jsonSchema(op.RequestBody.Content))
+		}
+		outputs = appendSchema(outputs, jsonSchema(op.Responses["200"].Content))
+	}
+
+	allInputs := true
+	enum := make([]interface{}, 0, len(names))
+	opActions := make(map[string]openapi.Action)
+	for _, name := range names {
+		action := actions[name]
+		a := openapi.Action{
+			Description: fmt.Sprintf("`%s` Action on `%s`", name, resourceType),
+		}
+		if action.Input != "" && g.resolveSchema(action.Input, schemaRoot, resourceType) {
+			a.Input = &openapi.Schema{Ref: fmt.Sprintf("#/components/schemas/%s", action.Input)}
+			inputs = appendSchema(inputs, a.Input)
+		} else {
+			allInputs = false
+		}
+		if action.Output != "" && g.resolveSchema(action.Output, schemaRoot, resourceType) {
+			a.Output = &openapi.Schema{Ref: fmt.Sprintf("#/components/schemas/%s", action.Output)}
+			outputs = appendSchema(outputs, a.Output)
+		}
+		opActions[name] = a
+		enum = append(enum, name)
+	}
+	op.SetActions(opActions)
+
+	param := openapi.Parameter{
+		Name:        "action",
+		In:          "query",
+		Description: "Action to perform",
+		Required:    post == nil,
+		Schema:      &openapi.Schema{Type: "string", Enum: enum},
+	}
+	if post != nil {
+		param.Description = fmt.Sprintf("Action to perform, creates a `%s` without one", resourceType)
+	}
+	op.Parameters = append(op.Parameters, param)
+
+	if len(inputs) > 0 {
+		if op.RequestBody == nil {
+			op.RequestBody = &openapi.RequestBody{
+				Description: "Input of the action.",
+				Required:    allInputs,
+			}
+		}
+		op.RequestBody.Content = map[string]openapi.MediaType{
+			"application/json": {Schema: oneOf(inputs)},
+		}
+	}
+	if len(outputs) > 0 {
+		response := op.Responses["200"]
+		if post == nil {
+			response.Description = "Returns the output of the action."
+		}
+		response.Content = map[string]openapi.MediaType{
+			"application/json": {Schema: oneOf(outputs)},
+		}
+		op.Responses["200"] = response
+	}
+	return op
+}
+
+// jsonSchema - the schema of the JSON content, if any
+func jsonSchema(content map[string]openapi.MediaType) *openapi.Schema {
+	return content["application/json"].Schema
+}
+
+// appendSchema - add a schema to a oneOf, once
+func appendSchema(schemas []openapi.Schema, schema *openapi.Schema) []openapi.Schema {
+	if schema == nil {
+		return schemas
+	}
+	for _, s := range schemas {
+		if s.Ref != "" && s.Ref == schema.Ref {
+			return schemas
+		}
+	}
+	return append(schemas, *schema)
+}
+
+// oneOf - the only schema, or oneOf all of them
+func oneOf(schemas []openapi.Schema) *openapi.Schema {
+	if len(schemas) == 1 {
+		return &schemas[0]
+	}
+	return &openapi.Schema{OneOf: schemas}
+}
+
+// resolveSchema - make sure a schema referenced by id is translated
+func (g *Generator) resolveSchema(id string, schemaRoot string, referencedBy string) bool {
+	if _, ok := g.swagger.Components.Schemas[id]; ok {
+		return true
+	}
+	schema, err := g.getSchema(schemaRoot, id, referencedBy)
+	if err != nil {
+		log.Errorf("Failed to get Schema for %s/%s - %v", schemaRoot, id, err)
+		return false
+	}
+	g.translateSchema(schema, schemaRoot)
+	return true
+}
+
+// createFilterParameters - query parameters for the collection filters.
+// Every field can be filtered on as `field=value` and with each modifier
+// as `field_modifier=value`.
+func createFilterParameters(rSchema norman.Schema) []openapi.Parameter {
+	names := make([]string, 0, len(rSchema.CollectionFilters))
+	for name := range rSchema.CollectionFilters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make([]openapi.Parameter, 0)
+	for _, name := range names {
+		parameters = append(parameters, openapi.Parameter{
+			Name:        name,
+			In:          "query",
+			Description: fmt.Sprintf("Filter by `%s`", name),
+			Schema: &openapi.Schema{
+				Type: "string",
+			},
+		})
+		for _, modifier := range rSchema.CollectionFilters[name].Modifiers {
+			if modifier == norman.ModifierEQ {
+				continue
+			}
+			parameters = append(parameters, openapi.Parameter{
+				Name:        fmt.Sprintf("%s_%s", name, modifier),
+				In:          "query",
+				Description: fmt.Sprintf("Filter by `%s` with modifier `%s`", name, modifier),
+				Schema: &openapi.Schema{
+					Type: "string",
+				},
+			})
+		}
+	}
+	if len(parameters) == 0 {
+		return nil
+	}
+	return parameters
+}
+
+func (g *Generator) createPathParameter(name string) {
+	_, ok := g.swagger.Components.Parameters[name]
+	if !ok {
+		g.swagger.Components.Parameters[name] = openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema: &openapi.Schema{
+				Type: "string",
+			},
+		}
+	}
+}
+
+// createCollectionSchema - the list of item schemas a collection returns
+func (g *Generator) createCollectionSchema(name string, item string) {
+	colAllOf := make([]openapi.Schema, 0)
+	resTypeRef := openapi.Schema{
+		Ref: "#/components/schemas/collection",
+	}
+	colAllOf = append(colAllOf, resTypeRef)
+	colData := openapi.Schema{
+		Type: "array",
+		Items: &openapi.Schema{
+			Ref: fmt.Sprintf("#/components/schemas/%s", item),
+		},
+	}
+	colProp := make(map[string]openapi.Schema)
+	colProp["data"] = colData
+
+	colSchema := openapi.Schema{
+		Type:       "object",
+		AllOf:      colAllOf,
+		Properties: colProp,
+	}
+	g.swagger.Components.Schemas[name] = colSchema
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedActions(m map[string]norman.Action) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
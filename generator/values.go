@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"math"
+	"strconv"
+)
+
+// TypedValue - a norman default or option as the JSON type of the schema it
+// is documented in, e.g. "10" or 10.0 as 10 for an integer
+func TypedValue(value interface{}, typ string) interface{} {
+	s, isString := value.(string)
+	switch typ {
+	case "integer":
+		if f, ok := value.(float64); ok && f == math.Trunc(f) {
+			return int64(f)
+		}
+		if i, err := strconv.ParseInt(s, 10, 64); isString && err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(s, 64); isString && err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(s); isString && err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// TypedValues - the options of a norman field as an enum of typ
+func TypedValues(options []string, typ string) []interface{} {
+	if len(options) == 0 {
+		return nil
+	}
+	values := make([]interface{}, 0, len(options))
+	for _, option := range options {
+		values = append(values, TypedValue(option, typ))
+	}
+	return values
+}
+
+// Number - a norman min or max as an OpenAPI numeric constraint
+func Number(i *int64) *float64 {
+	if i == nil {
+		return nil
+	}
+	f := float64(*i)
+	return &f
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypedValue(t *testing.T) {
+	for _, c := range []struct {
+		value    interface{}
+		typ      string
+		expected interface{}
+	}{
+		{"10", "integer", int64(10)},
+		{10.0, "integer", int64(10)},
+		{1.5, "integer", 1.5},
+		{"0.25", "number", 0.25},
+		{"true", "boolean", true},
+		{"10", "string", "10"},
+		{"abc", "integer", "abc"},
+		{nil, "integer", nil},
+	} {
+		if actual := TypedValue(c.value, c.typ); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("expected %#v as %s to be %#v, got %#v", c.value, c.typ, c.expected, actual)
+		}
+	}
+
+	if values := TypedValues(nil, "integer"); values != nil {
+		t.Errorf("expected no enum without options, got %v", values)
+	}
+}
+
+func TestGenerateNumbers(t *testing.T) {
+	swagger, _ := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	workload := swagger.Components.Schemas["workload"]
+	own := ownSchema(&workload)
+
+	deadline := own.Properties["progressDeadline"]
+	if deadline.Type != "integer" || !reflect.DeepEqual(deadline.Enum, []interface{}{int64(300), int64(600), int64(900)}) || deadline.Default != int64(600) {
+		t.Errorf("expected an integer enum and default, got %+v", deadline)
+	}
+
+	unavailable := own.Properties["maxUnavailable"]
+	if unavailable.Type != "number" || unavailable.Default != 0.25 {
+		t.Errorf("expected a number defaulting to 0.25, got %+v", unavailable)
+	}
+	if unavailable.Minimum == nil || *unavailable.Minimum != 0 || unavailable.Maximum == nil || *unavailable.Maximum != 1 {
+		t.Errorf("expected maxUnavailable between 0 and 1, got %+v", unavailable)
+	}
+
+	node := swagger.Components.Schemas["node"]
+	roles := ownSchema(&node).Properties["roles"]
+	if roles.Enum != nil || roles.Items == nil || len(roles.Items.Enum) != 3 {
+		t.Errorf("expected the options of an array[enum] on its items, got %+v", roles)
+	}
+}
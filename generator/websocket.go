@@ -0,0 +1,323 @@
+package generator
+
+import (
+	"fmt"
+	neturl "net/url"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	log "github.com/sirupsen/logrus"
+)
+
+// eventSchema - component schema of the events sent on subscribe
+const eventSchema = "subscribeEvent"
+
+// websocket - a link that upgrades the connection to a websocket instead of
+// returning a collection, documented as the GET of the upgrade handshake.
+type websocket struct {
+	Description string
+	// Query - set when the link is the resource it is on with a query added,
+	// e.g. shell=true on a cluster. The websocket is documented on the GET of
+	// the resource, opened by the query.
+	Query        string
+	Parameters   []openapi.Parameter
+	Subprotocols []string
+	// Events - names of the subscribeEvent messages the server sends
+	Events  []string
+	Receive []openapi.Message
+	Send    []openapi.Message
+}
+
+var websockets = map[string]websocket{
+	"subscribe": {
+		Description: "Stream changes to the resources visible to the caller as `subscribeEvent` messages.",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        "eventNames",
+				In:          "query",
+				Description: "Only send these events, repeat for each event. Defaults to all of them.",
+				Schema: &openapi.Schema{
+					Type:  "array",
+					Items: &openapi.Schema{Type: "string", Enum: []interface{}{"resource.change", "resource.remove"}},
+				},
+			},
+			{
+				Name:        "resourceType",
+				In:          "query",
+				Description: "Only send events for these resource types, repeat for each type.",
+				Schema: &openapi.Schema{
+					Type:  "array",
+					Items: &openapi.Schema{Type: "string"},
+				},
+			},
+		},
+		Events: []string{"resource.change", "resource.remove", "ping"},
+	},
+	"shell": {
+		Description:  "Open a `kubectl` shell in the cluster. Every frame is prefixed with the channel it belongs to and base64 encoded.",
+		Query:        "shell=true",
+		Subprotocols: []string{"base64.channel.k8s.io"},
+		Send: []openapi.Message{
+			{Name: "stdin", Summary: "Channel `0` - input to the shell", Payload: &openapi.Schema{Type: "string", Pattern: "^0"}},
+		},
+		Receive: []openapi.Message{
+			{Name: "stdout", Summary: "Channel `1` - output of the shell", Payload: &openapi.Schema{Type: "string", Pattern: "^1"}},
+			{Name: "stderr", Summary: "Channel `2` - errors of the shell", Payload: &openapi.Schema{Type: "string", Pattern: "^2"}},
+		},
+	},
+}
+
+var eventSummaries = map[string]string{
+	"resource.change": "A resource was created or changed, `data` is the resource",
+	"resource.remove": "A resource was removed, `data` is the resource as it was last seen",
+	"ping":            "Sent every few seconds to keep the connection open, `data` is empty",
+}
+
+// pushLink - queue a link to be crawled. Websockets can't be read like
+// collections, they are documented as soon as they are found.
+func (g *Generator) pushLink(from string, col string, link string, base string, depth int) {
+	if ws, ok := websockets[col]; ok {
+		g.createWebsocket(crawlTarget{
+			Col:   col,
+			Link:  link,
+			Base:  base,
+			Depth: depth,
+		}, ws)
+		return
+	}
+	g.graph.push(from, col, link, base, depth)
+}
+
+// createWebsocket - document the upgrade handshake of a websocket link.
+// Links to the same socket only add the query parameters they set, e.g.
+// the clusterId of /v3/subscribe?clusterId=c-1 on a cluster.
+func (g *Generator) createWebsocket(target crawlTarget, ws websocket) {
+	key := normalizeLink(withoutQuery(target.Link)) + "?" + ws.Query
+	if path, ok := g.sockets[key]; ok {
+		g.addLinkQuery(path, target, ws)
+		return
+	}
+	g.sockets[key] = ""
+	if g.skipCollection(target, "") {
+		return
+	}
+
+	path := target.Base + target.Col
+	if ws.Query != "" {
+		path = strings.TrimSuffix(target.Base, "/")
+	}
+	log.Infof("Document websocket: %s -> %s", path, target.Link)
+
+	query, _ := neturl.ParseQuery(ws.Query)
+	socket := &openapi.Websocket{
+		Subprotocols: ws.Subprotocols,
+		Send:         ws.Send,
+	}
+	for _, name := range sortedQuery(query) {
+		if socket.Query == nil {
+			socket.Query = make(map[string]string)
+		}
+		socket.Query[name] = query.Get(name)
+	}
+	for _, name := range ws.Events {
+		socket.Receive = append(socket.Receive, openapi.Message{
+			Name:    name,
+			Summary: eventSummaries[name],
+			Payload: &openapi.Schema{Ref: fmt.Sprintf("#/components/schemas/%s", eventSchema)},
+		})
+		g.events[name] = true
+	}
+	socket.Receive = append(socket.Receive, ws.Receive...)
+
+	switching := openapi.Response{Description: "Switching Protocols, the connection is a websocket from here on"}
+	parameters := append(upgradeParameters(ws.Subprotocols), ws.Parameters...)
+	pathItem, ok := g.swagger.Paths[path]
+	if !ok {
+		pathItem.Parameters = basePathParameters(target.Base)
+	}
+	if pathItem.Get == nil {
+		pathItem.Get = &openapi.Operation{
+			Tags:        []string{target.Col},
+			Summary:     target.Col,
+			Description: ws.Description,
+			Parameters:  append(parameters, socketQuery(target.Col, query, true)...),
+			Responses:   map[string]openapi.Response{"101": switching},
+		}
+		pathItem.Get.SetWebsocket(socket)
+	} else {
+		// the GET of the resource the link is on, only upgraded with its query
+		for i := range parameters {
+			parameters[i].Required = false
+		}
+		op := pathItem.Get
+		op.Description = fmt.Sprintf("%s\n\nWith `?%s`: %s", op.Description, ws.Query, ws.Description)
+		op.Parameters = append(append(op.Parameters, socketQuery(target.Col, query, false)...), parameters...)
+		op.Responses["101"] = switching
+		op.SetWebsocket(socket)
+	}
+	g.swagger.Paths[path] = pathItem
+
+	g.sockets[key] = path
+	g.addLinkQuery(path, target, ws)
+	g.report.crawled(target, "")
+}
+
+// socketQuery - the query parameters that open the websocket of col
+func socketQuery(col string, query neturl.Values, required bool) []openapi.Parameter {
+	parameters := make([]openapi.Parameter, 0, len(query))
+	for _, name := range sortedQuery(query) {
+		parameters = append(parameters, openapi.Parameter{
+			Name:        name,
+			In:          "query",
+			Description: fmt.Sprintf("Set to `%s` to open the `%s` websocket", query.Get(name), col),
+			Required:    required,
+			Schema: &openapi.Schema{
+				Type: "string",
+				Enum: TypedValues(query[name], "string"),
+			},
+		})
+	}
+	return parameters
+}
+
+// withoutQuery - link without its query string
+func withoutQuery(link string) string {
+	if i := strings.Index(link, "?"); i >= 0 {
+		return link[:i]
+	}
+	return link
+}
+
+// addLinkQuery - document the query parameters the link of a websocket sets
+func (g *Generator) addLinkQuery(path string, target crawlTarget, ws websocket) {
+	if path == "" {
+		return
+	}
+	u, err := neturl.Parse(target.Link)
+	if err != nil {
+		return
+	}
+	op := g.swagger.Paths[path].Get
+	query := u.Query()
+	for _, name := range sortedQuery(query) {
+		if strings.HasPrefix(ws.Query, name+"=") || hasParameter(op.Parameters, name) {
+			continue
+		}
+		op.Parameters = append(op.Parameters, openapi.Parameter{
+			Name:        name,
+			In:          "query",
+			Description: fmt.Sprintf("Set by the `%s` link of `%s`", target.Col, strings.TrimSuffix(target.Base, "/")),
+			Schema: &openapi.Schema{
+				Type: "string",
+			},
+		})
+	}
+}
+
+// createEventSchema - the subscribeEvent schema, data is any of the documented resources
+func (g *Generator) createEventSchema() {
+	if len(g.events) == 0 {
+		return
+	}
+	names := make([]string, 0, len(g.events))
+	for name := range g.events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]bool)
+	types := make([]string, 0)
+	for _, entry := range g.report.Collections {
+		if entry.Schema != "" && !seen[entry.Schema] {
+			seen[entry.Schema] = true
+			types = append(types, entry.Schema)
+		}
+	}
+	sort.Strings(types)
+
+	data := make([]openapi.Schema, 0, len(types)+1)
+	for _, resourceType := range types {
+		data = append(data, openapi.Schema{Ref: fmt.Sprintf("#/components/schemas/%s", resourceType)})
+	}
+	data = append(data, openapi.Schema{Type: "object", Description: "Empty for `ping`"})
+
+	g.swagger.Components.Schemas[eventSchema] = openapi.Schema{
+		Type:        "object",
+		Description: "Event sent on `subscribe`, `data` is the resource of `resourceType`.",
+		Required:    []string{"name"},
+		Properties: map[string]openapi.Schema{
+			"name":         {Type: "string", Enum: TypedValues(names, "string")},
+			"resourceType": {Type: "string", Enum: TypedValues(types, "string")},
+			"data":         {AnyOf: data},
+		},
+	}
+}
+
+// upgradeParameters - headers of the websocket handshake
+func upgradeParameters(subprotocols []string) []openapi.Parameter {
+	parameters := []openapi.Parameter{
+		upgradeHeader("Connection", "", "Upgrade"),
+		upgradeHeader("Upgrade", "", "websocket"),
+		upgradeHeader("Sec-WebSocket-Version", "", "13"),
+		{
+			Name:        "Sec-WebSocket-Key",
+			In:          "header",
+			Description: "Random base64 encoded 16 byte nonce",
+			Required:    true,
+			Schema: &openapi.Schema{
+				Type:    "string",
+				Example: "dGhlIHNhbXBsZSBub25jZQ==",
+			},
+		},
+	}
+	if len(subprotocols) > 0 {
+		parameters = append(parameters, upgradeHeader("Sec-WebSocket-Protocol", "Subprotocol spoken on the socket", subprotocols...))
+	}
+	return parameters
+}
+
+func upgradeHeader(name string, description string, values ...string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        name,
+		In:          "header",
+		Description: description,
+		Required:    true,
+		Schema: &openapi.Schema{
+			Type: "string",
+			Enum: TypedValues(values, "string"),
+		},
+	}
+}
+
+// basePathParameters - references to the path parameters in base
+func basePathParameters(base string) []openapi.Parameter {
+	parameters := make([]openapi.Parameter, 0)
+	for _, p := range pathParameterNames.FindAllStringSubmatch(base, -1) {
+		parameters = append(parameters, openapi.Parameter{
+			Ref: fmt.Sprintf("#/components/parameters/%s", p[1]),
+		})
+	}
+	if len(parameters) == 0 {
+		return nil
+	}
+	return parameters
+}
+
+func hasParameter(parameters []openapi.Parameter, name string) bool {
+	for _, p := range parameters {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedQuery(values neturl.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
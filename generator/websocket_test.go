@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestGenerateWebsockets(t *testing.T) {
+	swagger, report := generateFake(t, DefaultMaxDepth, defaultRules(t))
+
+	assertPaths(t, swagger, []string{
+		"/subscribe",
+		"/clusters/{clusterId}",
+	}, []string{
+		"/clusters/{clusterId}/subscribe",
+		"/clusters/{clusterId}/shell",
+	})
+
+	subscribe := swagger.Paths["/subscribe"].Get
+	if subscribe == nil || subscribe.Websocket() == nil {
+		t.Fatalf("expected a websocket GET on /subscribe, got %+v", swagger.Paths["/subscribe"])
+	}
+	if _, ok := subscribe.Responses["101"]; !ok {
+		t.Errorf("expected 101 Switching Protocols, got %+v", subscribe.Responses)
+	}
+	params := make(map[string]string)
+	for _, p := range subscribe.Parameters {
+		params[p.Name] = p.In
+	}
+	for name, in := range map[string]string{
+		"Connection":        "header",
+		"Upgrade":           "header",
+		"Sec-WebSocket-Key": "header",
+		"eventNames":        "query",
+		"resourceType":      "query",
+		"clusterId":         "query",
+	} {
+		if params[name] != in {
+			t.Errorf("expected %s in %s on /subscribe, got %+v", name, in, params)
+		}
+	}
+	events := make([]string, 0)
+	for _, m := range subscribe.Websocket().Receive {
+		events = append(events, m.Name)
+		if m.Payload == nil || m.Payload.Ref != "#/components/schemas/subscribeEvent" {
+			t.Errorf("expected %s to be a subscribeEvent, got %+v", m.Name, m.Payload)
+		}
+	}
+	if len(events) != 3 || events[0] != "resource.change" || events[1] != "resource.remove" || events[2] != "ping" {
+		t.Errorf("unexpected events %v", events)
+	}
+
+	event := swagger.Components.Schemas["subscribeEvent"]
+	data := make(map[string]bool)
+	for _, s := range event.Properties["data"].AnyOf {
+		data[s.Ref] = true
+	}
+	for _, ref := range []string{"#/components/schemas/cluster", "#/components/schemas/workload"} {
+		if !data[ref] {
+			t.Errorf("expected event data to reference %s, got %+v", ref, event.Properties["data"])
+		}
+	}
+
+	cluster := swagger.Paths["/clusters/{clusterId}"]
+	if len(cluster.Parameters) != 1 || cluster.Parameters[0].Ref != "#/components/parameters/clusterId" {
+		t.Errorf("expected the clusterId parameter on the shell, got %+v", cluster.Parameters)
+	}
+	shell := cluster.Get
+	if shell == nil || shell.Websocket() == nil || len(shell.Websocket().Subprotocols) != 1 || len(shell.Websocket().Send) != 1 || shell.Websocket().Query["shell"] != "true" {
+		t.Fatalf("expected a shell websocket with a subprotocol and input on the GET of a cluster, got %+v", shell)
+	}
+	if shell.Upgrades() || shell.Responses["200"].Content == nil {
+		t.Errorf("expected the GET of a cluster to still return the cluster, got %+v", shell.Responses)
+	}
+	query := false
+	for _, p := range shell.Parameters {
+		if p.In == "query" && p.Name == "shell" {
+			query = len(p.Schema.Enum) == 1 && p.Schema.Enum[0] == "true"
+		}
+		if p.Required {
+			t.Errorf("expected no required parameter on the GET of a cluster, got %+v", p)
+		}
+	}
+	if !query {
+		t.Errorf("expected the shell query parameter, got %+v", shell.Parameters)
+	}
+
+	if !hasEntry(report.Collections, "/subscribe", "") {
+		t.Errorf("expected /subscribe in the report, got %+v", report.Collections)
+	}
+}
+
+func TestGenerateWebsocketsExcluded(t *testing.T) {
+	rules := defaultRules(t)
+	rules.Exclude = append(rules.Exclude, Rule{Collection: "subscribe", Reason: "No websockets"})
+	if err := rules.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	swagger, report := generateFake(t, DefaultMaxDepth, rules)
+
+	if _, ok := swagger.Paths["/subscribe"]; ok {
+		t.Error("expected /subscribe to be excluded")
+	}
+	if _, ok := swagger.Components.Schemas["subscribeEvent"]; ok {
+		t.Error("expected no subscribeEvent without /subscribe")
+	}
+	skipped := 0
+	for _, entry := range report.Skipped {
+		if entry.Collection == "subscribe" {
+			skipped++
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("expected subscribe to be skipped once, got %+v", report.Skipped)
+	}
+}
@@ -0,0 +1,232 @@
+// Package jsonschema exports the OpenAPI component schemas as standalone
+// JSON Schema files, one per schema, for editor validation of Rancher objects.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+const schemaPrefix = "#/components/schemas/"
+
+// Draft - a JSON Schema dialect
+type Draft struct {
+	Name   string
+	Schema string
+}
+
+var (
+	// Draft07 - http://json-schema.org/draft-07/schema
+	Draft07 = Draft{
+		Name:   "draft-07",
+		Schema: "http://json-schema.org/draft-07/schema#",
+	}
+	// Draft202012 - https://json-schema.org/draft/2020-12/schema
+	Draft202012 = Draft{
+		Name:   "2020-12",
+		Schema: "https://json-schema.org/draft/2020-12/schema",
+	}
+	// Drafts - every supported dialect
+	Drafts = []Draft{Draft07, Draft202012}
+)
+
+// Catalog - index of the exported schemas.
+// Follows the schemastore.org catalog format so editors can pick it up.
+type Catalog struct {
+	Schema  string         `json:"$schema"`
+	Version int            `json:"version"`
+	Schemas []CatalogEntry `json:"schemas"`
+}
+
+// CatalogEntry - a schema in the catalog
+type CatalogEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+// Write - write every component schema for each draft to dir/<draft>/<name>.json
+// with a catalog.json listing them.
+func Write(swagger *openapi.OpenAPI, dir string) error {
+	for _, draft := range Drafts {
+		err := WriteDraft(swagger, draft, filepath.Join(dir, draft.Name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDraft - write every component schema in one dialect to dir
+func WriteDraft(swagger *openapi.OpenAPI, draft Draft, dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(swagger.Components.Schemas))
+	for name := range swagger.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	catalog := Catalog{
+		Schema:  "https://json.schemastore.org/schema-catalog.json",
+		Version: 1,
+		Schemas: make([]CatalogEntry, 0, len(names)),
+	}
+	for _, name := range names {
+		schema := swagger.Components.Schemas[name]
+		doc := Convert(name, schema, draft)
+		err = writeJSON(filepath.Join(dir, fileName(name)), doc)
+		if err != nil {
+			return err
+		}
+		catalog.Schemas = append(catalog.Schemas, CatalogEntry{
+			Name:        name,
+			Description: schema.Description,
+			URL:         fileName(name),
+		})
+	}
+
+	return writeJSON(filepath.Join(dir, "catalog.json"), catalog)
+}
+
+// Convert - a component schema as a standalone JSON Schema document.
+// References to other components point at their sibling files.
+func Convert(name string, schema openapi.Schema, draft Draft) map[string]interface{} {
+	doc := convert(schema)
+	doc["$schema"] = draft.Schema
+	doc["title"] = name
+	return doc
+}
+
+// enum - the allowed values, with null when the schema is nullable since a
+// JSON Schema enum is checked on its own, along with the type
+func enum(schema openapi.Schema) []interface{} {
+	if !schema.Nullable {
+		return schema.Enum
+	}
+	for _, value := range schema.Enum {
+		if value == nil {
+			return schema.Enum
+		}
+	}
+	return append(append([]interface{}{}, schema.Enum...), nil)
+}
+
+func convert(schema openapi.Schema) map[string]interface{} {
+	out := make(map[string]interface{})
+
+	if schema.Ref != "" {
+		ref := map[string]interface{}{
+			"$ref": refFile(schema.Ref),
+		}
+		if !schema.Nullable {
+			return ref
+		}
+		out["anyOf"] = []interface{}{ref, map[string]interface{}{"type": "null"}}
+		return out
+	}
+
+	if schema.Type != "" {
+		if schema.Nullable {
+			out["type"] = []string{schema.Type, "null"}
+		} else {
+			out["type"] = schema.Type
+		}
+	}
+
+	set := func(key string, value interface{}, ok bool) {
+		if ok {
+			out[key] = value
+		}
+	}
+	set("description", schema.Description, schema.Description != "")
+	set("format", schema.Format, schema.Format != "")
+	set("pattern", schema.Pattern, schema.Pattern != "")
+	set("enum", enum(schema), len(schema.Enum) > 0)
+	set("default", schema.Default, schema.Default != nil)
+	set("examples", []interface{}{schema.Example}, schema.Example != nil)
+	set("readOnly", true, schema.ReadOnly)
+	set("writeOnly", true, schema.WriteOnly)
+	set("deprecated", true, schema.Deprecated)
+	set("uniqueItems", true, schema.UniqueItems)
+	set("required", schema.Required, len(schema.Required) > 0)
+	set("multipleOf", schema.MultipleOf, schema.MultipleOf != nil)
+	// exclusive bounds are flags in OpenAPI 3.0 and the bound itself in JSON Schema
+	if schema.Maximum != nil {
+		set("maximum", *schema.Maximum, !schema.ExclusiveMaximum)
+		set("exclusiveMaximum", *schema.Maximum, schema.ExclusiveMaximum)
+	}
+	if schema.Minimum != nil {
+		set("minimum", *schema.Minimum, !schema.ExclusiveMinimum)
+		set("exclusiveMinimum", *schema.Minimum, schema.ExclusiveMinimum)
+	}
+	set("maxLength", schema.MaxLength, schema.MaxLength != nil)
+	set("minLength", schema.MinLength, schema.MinLength != nil)
+	set("maxItems", schema.MaxItems, schema.MaxItems != nil)
+	set("minItems", schema.MinItems, schema.MinItems != nil)
+	set("maxProperties", schema.MaxProperties, schema.MaxProperties != nil)
+	set("minProperties", schema.MinProperties, schema.MinProperties != nil)
+
+	if len(schema.Properties) > 0 {
+		properties := make(map[string]interface{}, len(schema.Properties))
+		for name, property := range schema.Properties {
+			properties[name] = convert(property)
+		}
+		out["properties"] = properties
+	}
+	if schema.AdditionalProperties != nil {
+		out["additionalProperties"] = convert(*schema.AdditionalProperties)
+	}
+	if schema.Items != nil {
+		out["items"] = convert(*schema.Items)
+	}
+	set("allOf", convertAll(schema.AllOf), len(schema.AllOf) > 0)
+	set("oneOf", convertAll(schema.OneOf), len(schema.OneOf) > 0)
+	set("anyOf", convertAll(schema.AnyOf), len(schema.AnyOf) > 0)
+	if schema.Not != nil {
+		out["not"] = convert(*schema.Not)
+	}
+	for name, value := range schema.Extensions {
+		out[name] = value
+	}
+
+	return out
+}
+
+func convertAll(schemas []openapi.Schema) []interface{} {
+	out := make([]interface{}, 0, len(schemas))
+	for _, schema := range schemas {
+		out = append(out, convert(schema))
+	}
+	return out
+}
+
+// refFile - #/components/schemas/<name> -> <name>.json
+func refFile(ref string) string {
+	if !strings.HasPrefix(ref, schemaPrefix) {
+		return ref
+	}
+	return fileName(strings.TrimPrefix(ref, schemaPrefix))
+}
+
+func fileName(name string) string {
+	return fmt.Sprintf("%s.json", name)
+}
+
+func writeJSON(file string, v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, out, 0644)
+}
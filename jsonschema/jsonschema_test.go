@@ -0,0 +1,116 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func TestConvert(t *testing.T) {
+	zero, one := 0.0, 1.0
+	schema := openapi.Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]openapi.Schema{
+			"name":        {Type: "string", Pattern: "^[a-z]*$"},
+			"description": {Type: "string", Nullable: true},
+			"state":       {Type: "string", ReadOnly: true},
+			"config":      {Ref: "#/components/schemas/rkeConfig", Nullable: true},
+			"conditions":  {Type: "array", Items: &openapi.Schema{Ref: "#/components/schemas/clusterCondition"}},
+			"labels":      {Type: "object", Example: map[string]string{"key": "value"}},
+			"ratio":       {Type: "number", Minimum: &zero, Maximum: &one, ExclusiveMaximum: true, Enum: []interface{}{0.25, 0.5}},
+			"driver":      {Type: "string", Nullable: true, Enum: []interface{}{"rke", "k3s"}},
+		},
+	}
+
+	actual := Convert("cluster", schema, Draft202012)
+	out, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"title":    "cluster",
+		"type":     "object",
+		"required": []interface{}{"name"},
+		"properties": map[string]interface{}{
+			"name":        map[string]interface{}{"type": "string", "pattern": "^[a-z]*$"},
+			"description": map[string]interface{}{"type": []interface{}{"string", "null"}},
+			"state":       map[string]interface{}{"type": "string", "readOnly": true},
+			"config": map[string]interface{}{"anyOf": []interface{}{
+				map[string]interface{}{"$ref": "rkeConfig.json"},
+				map[string]interface{}{"type": "null"},
+			}},
+			"conditions": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "clusterCondition.json"}},
+			"labels":     map[string]interface{}{"type": "object", "examples": []interface{}{map[string]interface{}{"key": "value"}}},
+			"ratio":      map[string]interface{}{"type": "number", "minimum": 0.0, "exclusiveMaximum": 1.0, "enum": []interface{}{0.25, 0.5}},
+			"driver":     map[string]interface{}{"type": []interface{}{"string", "null"}, "enum": []interface{}{"rke", "k3s", nil}},
+		},
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("expected %v, got %v", expected, doc)
+	}
+	if driver := schema.Properties["driver"]; len(driver.Enum) != 2 {
+		t.Errorf("expected the enum of the OpenAPI schema to be left as it is, got %v", driver.Enum)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonschema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	swagger := &openapi.OpenAPI{
+		Components: openapi.Components{
+			Schemas: map[string]openapi.Schema{
+				"cluster": {Type: "object", Description: "A cluster"},
+				"node":    {Type: "object"},
+			},
+		},
+	}
+	if err := Write(swagger, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, draft := range Drafts {
+		catalog := Catalog{}
+		out, err := ioutil.ReadFile(filepath.Join(dir, draft.Name, "catalog.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(out, &catalog); err != nil {
+			t.Fatal(err)
+		}
+		expected := []CatalogEntry{
+			{Name: "cluster", Description: "A cluster", URL: "cluster.json"},
+			{Name: "node", URL: "node.json"},
+		}
+		if !reflect.DeepEqual(catalog.Schemas, expected) {
+			t.Errorf("%s: expected %+v, got %+v", draft.Name, expected, catalog.Schemas)
+		}
+
+		doc := map[string]interface{}{}
+		out, err = ioutil.ReadFile(filepath.Join(dir, draft.Name, "cluster.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(out, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc["$schema"] != draft.Schema {
+			t.Errorf("%s: expected $schema %s, got %v", draft.Name, draft.Schema, doc["$schema"])
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/rancher/gen-api-docs/fake"
+	"github.com/rancher/gen-api-docs/generator"
+)
+
+func newFakeRancher(t *testing.T) *fake.Server {
+	t.Helper()
+	fixture, err := fake.LoadFixture("./testdata/rancher.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewServer(fixture)
+}
+
+func newGenerator(t *testing.T, srv *fake.Server) *generator.Generator {
+	t.Helper()
+	rules, err := generator.LoadRules("./data/rules.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return generator.New(generator.Options{
+		URL:      srv.URL + "/v3",
+		Rules:    rules,
+		MaxDepth: generator.DefaultMaxDepth,
+	})
+}
+
+func TestSplit(t *testing.T) {
+	if values := split(" markdown, ,html,"); len(values) != 2 || values[0] != "markdown" || values[1] != "html" {
+		t.Errorf("expected markdown and html, got %q", values)
+	}
+	if values := split(""); len(values) != 0 {
+		t.Errorf("expected no values, got %q", values)
+	}
+}
@@ -0,0 +1,221 @@
+// Package extensions marshals the specification extensions of OpenAPI
+// objects, the `x-` fields, inline with the fields of the object.
+// Used by the types of every OpenAPI version.
+package extensions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Prefix - every extension starts with x-
+const Prefix = "x-"
+
+// Extensions - the x- fields of an object by name, e.g. x-rancher-type.
+// Every x- field of an object is kept here, the ones in its Types are
+// read as their Go type.
+type Extensions map[string]interface{}
+
+// Types - the extensions of an object with a Go type of their own, by name,
+// e.g. x-websocket: &Websocket{}. The values are only used for their type.
+type Types map[string]interface{}
+
+// With - a copy of the extensions with name set to value, or without name
+// when value is nil or empty. The extensions of a copied object aren't changed.
+func (ext Extensions) With(name string, value interface{}) Extensions {
+	out := make(Extensions, len(ext)+1)
+	for key, v := range ext {
+		out[key] = v
+	}
+	if empty(value) {
+		delete(out, name)
+	} else {
+		out[name] = value
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// MarshalJSON - object as JSON, a pointer to a struct without the methods
+// of its type, with the extensions appended to its fields
+func MarshalJSON(object interface{}, ext Extensions) ([]byte, error) {
+	out, err := json.Marshal(object)
+	if err != nil || len(ext) == 0 {
+		return out, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(out, []byte("}")))
+	first := bytes.Equal(out, []byte("{}"))
+	for _, name := range ext.names() {
+		value, err := json.Marshal(ext[name])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to marshal extension %s - %v", name, err)
+		}
+		key, _ := json.Marshal(name)
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON - read data into object, a pointer to a struct without the
+// methods of its type, and its x- fields into ext, as their type in types
+func UnmarshalJSON(data []byte, object interface{}, ext *Extensions, types Types) error {
+	err := json.Unmarshal(data, object)
+	if err != nil {
+		return err
+	}
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*ext = nil
+	for name, value := range raw {
+		if !strings.HasPrefix(name, Prefix) {
+			continue
+		}
+		v, err := types.decode(name, value)
+		if err != nil {
+			return err
+		}
+		if *ext == nil {
+			*ext = make(Extensions)
+		}
+		(*ext)[name] = v
+	}
+	return nil
+}
+
+// MarshalYAML - object as a YAML map, with the extensions after its fields
+func MarshalYAML(object interface{}, ext Extensions) (interface{}, error) {
+	if len(ext) == 0 {
+		return object, nil
+	}
+	out, err := yaml.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	fields := yaml.MapSlice{}
+	if err := yaml.Unmarshal(out, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, name := range ext.names() {
+		fields = append(fields, yaml.MapItem{Key: name, Value: ext[name]})
+	}
+	return fields, nil
+}
+
+// UnmarshalYAML - read into object, a pointer to a struct without the
+// methods of its type, and its x- fields into ext, as their type in types
+func UnmarshalYAML(unmarshal func(interface{}) error, object interface{}, ext *Extensions, types Types) error {
+	err := unmarshal(object)
+	if err != nil {
+		return err
+	}
+	raw := make(map[string]interface{})
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	*ext = nil
+	for name, value := range raw {
+		if !strings.HasPrefix(name, Prefix) {
+			continue
+		}
+		v := jsonValue(value)
+		if _, ok := types[name]; ok {
+			// read through JSON, the tags every type has
+			data, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("Invalid extension %s - %v", name, err)
+			}
+			if v, err = types.decode(name, data); err != nil {
+				return err
+			}
+		}
+		if *ext == nil {
+			*ext = make(Extensions)
+		}
+		(*ext)[name] = v
+	}
+	return nil
+}
+
+// decode - the JSON value of the extension name, as its type if it has one
+func (types Types) decode(name string, data []byte) (interface{}, error) {
+	typ, ok := types[name]
+	if !ok {
+		var v interface{}
+		err := json.Unmarshal(data, &v)
+		return v, err
+	}
+	v := reflect.New(reflect.TypeOf(typ))
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return nil, fmt.Errorf("Invalid extension %s - %v", name, err)
+	}
+	return v.Elem().Interface(), nil
+}
+
+// names - the sorted names of the extensions, without anything not starting with x-
+func (ext Extensions) names() []string {
+	names := make([]string, 0, len(ext))
+	for name := range ext {
+		if strings.HasPrefix(name, Prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// empty - nil, a nil pointer or an empty slice, map or string
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// jsonValue - a YAML value with the map[interface{}]interface{} maps YAML
+// decodes to converted to map[string]interface{}, so it marshals as JSON
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			out[fmt.Sprint(key)] = jsonValue(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = jsonValue(value)
+		}
+		return out
+	}
+	return value
+}
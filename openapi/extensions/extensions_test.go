@@ -0,0 +1,120 @@
+package extensions
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+type object struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
+}
+
+type sample struct {
+	Lang string `yaml:"lang" json:"lang"`
+}
+
+var objectTypes = Types{"x-codeSamples": []sample{}}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	type plain object
+	return MarshalJSON(plain(o), o.Extensions)
+}
+
+func (o *object) UnmarshalJSON(data []byte) error {
+	type plain object
+	return UnmarshalJSON(data, (*plain)(o), &o.Extensions, objectTypes)
+}
+
+func (o object) MarshalYAML() (interface{}, error) {
+	type plain object
+	return MarshalYAML(plain(o), o.Extensions)
+}
+
+func (o *object) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain object
+	return UnmarshalYAML(unmarshal, (*plain)(o), &o.Extensions, objectTypes)
+}
+
+func TestJSON(t *testing.T) {
+	o := object{
+		Name: "cluster",
+		Extensions: Extensions{
+			"x-rancher-type": "dnsLabel",
+			"x-a":            map[string]interface{}{"b": true},
+			"notExtension":   "left out",
+			"x-codeSamples":  []sample{{Lang: "Go"}},
+		},
+	}
+	out, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"name":"cluster","x-a":{"b":true},"x-codeSamples":[{"lang":"Go"}],"x-rancher-type":"dnsLabel"}`; string(out) != expected {
+		t.Errorf("expected %s, got %s", expected, out)
+	}
+
+	read := object{}
+	if err := json.Unmarshal([]byte(`{"name":"cluster","x-rancher-type":"dnsLabel","x-codeSamples":[{"lang":"Go"}],"other":1}`), &read); err != nil {
+		t.Fatal(err)
+	}
+	expected := Extensions{"x-rancher-type": "dnsLabel", "x-codeSamples": []sample{{Lang: "Go"}}}
+	if read.Name != "cluster" || !reflect.DeepEqual(read.Extensions, expected) {
+		t.Errorf("expected %+v with x-codeSamples read as its type, got %+v", expected, read)
+	}
+
+	if err := json.Unmarshal([]byte(`{"x-codeSamples":"curl"}`), &read); err == nil {
+		t.Errorf("expected an error reading an extension that isn't its type")
+	}
+
+	empty, err := json.Marshal(object{Extensions: Extensions{"x-a": 1}})
+	if err != nil || string(empty) != `{"x-a":1}` {
+		t.Errorf("expected only the extension, got %s %v", empty, err)
+	}
+}
+
+func TestYAML(t *testing.T) {
+	o := object{
+		Name:       "cluster",
+		Extensions: Extensions{"x-rancher-type": "dnsLabel"},
+	}
+	out, err := yaml.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "name: cluster\nx-rancher-type: dnsLabel\n"; string(out) != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+
+	read := object{}
+	if err := yaml.Unmarshal([]byte("name: cluster\nx-a:\n  b: [1]\nx-codeSamples:\n- lang: Go\n"), &read); err != nil {
+		t.Fatal(err)
+	}
+	// YAML maps are read as JSON objects so they marshal as JSON
+	expected := Extensions{
+		"x-a":           map[string]interface{}{"b": []interface{}{1}},
+		"x-codeSamples": []sample{{Lang: "Go"}},
+	}
+	if read.Name != "cluster" || !reflect.DeepEqual(read.Extensions, expected) {
+		t.Errorf("expected %+v, got %+v", expected, read)
+	}
+	if _, err := json.Marshal(read); err != nil {
+		t.Errorf("expected the extensions read from YAML to marshal as JSON - %v", err)
+	}
+}
+
+func TestWith(t *testing.T) {
+	ext := Extensions{"x-a": 1}
+	with := ext.With("x-b", []sample{{Lang: "Go"}})
+	if len(ext) != 1 || len(with) != 2 {
+		t.Errorf("expected a copy with x-b, got %+v from %+v", with, ext)
+	}
+	var none *sample
+	if without := with.With("x-b", none).With("x-a", nil); without != nil {
+		t.Errorf("expected empty values to remove the extensions, got %+v", without)
+	}
+}
@@ -0,0 +1,239 @@
+package openapi
+
+import (
+	"encoding/json"
+
+	"github.com/rancher/gen-api-docs/openapi/extensions"
+)
+
+// Extensions - the x- fields of an object, marshalled inline with its other fields
+type Extensions = extensions.Extensions
+
+// Reference - https://swagger.io/specification/v2/#referenceObject
+// An object with a $ref is only the reference, its other fields are left out
+// when it is marshalled and ignored when it is read.
+type Reference struct {
+	Ref string `yaml:"$ref" json:"$ref"`
+}
+
+// MarshalJSON - the tag with its extensions
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type tag Tag
+	return extensions.MarshalJSON(tag(t), t.Extensions)
+}
+
+// UnmarshalJSON - read the tag and its extensions
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type tag Tag
+	return extensions.UnmarshalJSON(data, (*tag)(t), &t.Extensions, nil)
+}
+
+// MarshalYAML - the tag with its extensions
+func (t Tag) MarshalYAML() (interface{}, error) {
+	type tag Tag
+	return extensions.MarshalYAML(tag(t), t.Extensions)
+}
+
+// UnmarshalYAML - read the tag and its extensions
+func (t *Tag) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type tag Tag
+	return extensions.UnmarshalYAML(unmarshal, (*tag)(t), &t.Extensions, nil)
+}
+
+// MarshalJSON - the path with its extensions
+func (p Path) MarshalJSON() ([]byte, error) {
+	type path Path
+	return extensions.MarshalJSON(path(p), p.Extensions)
+}
+
+// UnmarshalJSON - read the path and its extensions
+func (p *Path) UnmarshalJSON(data []byte) error {
+	type path Path
+	return extensions.UnmarshalJSON(data, (*path)(p), &p.Extensions, nil)
+}
+
+// MarshalYAML - the path with its extensions
+func (p Path) MarshalYAML() (interface{}, error) {
+	type path Path
+	return extensions.MarshalYAML(path(p), p.Extensions)
+}
+
+// UnmarshalYAML - read the path and its extensions
+func (p *Path) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type path Path
+	return extensions.UnmarshalYAML(unmarshal, (*path)(p), &p.Extensions, nil)
+}
+
+// MarshalJSON - the operation with its extensions
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return extensions.MarshalJSON(operation(o), o.Extensions)
+}
+
+// UnmarshalJSON - read the operation and its extensions
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type operation Operation
+	return extensions.UnmarshalJSON(data, (*operation)(o), &o.Extensions, nil)
+}
+
+// MarshalYAML - the operation with its extensions
+func (o Operation) MarshalYAML() (interface{}, error) {
+	type operation Operation
+	return extensions.MarshalYAML(operation(o), o.Extensions)
+}
+
+// UnmarshalYAML - read the operation and its extensions
+func (o *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type operation Operation
+	return extensions.UnmarshalYAML(unmarshal, (*operation)(o), &o.Extensions, nil)
+}
+
+// MarshalJSON - the response with its extensions, or only its reference
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Ref != "" {
+		return json.Marshal(Reference{Ref: r.Ref})
+	}
+	type response Response
+	return extensions.MarshalJSON(response(r), r.Extensions)
+}
+
+// UnmarshalJSON - read the response and its extensions
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	if err := extensions.UnmarshalJSON(data, (*response)(r), &r.Extensions, nil); err != nil {
+		return err
+	}
+	if r.Ref != "" {
+		*r = Response{Ref: r.Ref}
+	}
+	return nil
+}
+
+// MarshalYAML - the response with its extensions, or only its reference
+func (r Response) MarshalYAML() (interface{}, error) {
+	if r.Ref != "" {
+		return Reference{Ref: r.Ref}, nil
+	}
+	type response Response
+	return extensions.MarshalYAML(response(r), r.Extensions)
+}
+
+// UnmarshalYAML - read the response and its extensions
+func (r *Response) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type response Response
+	if err := extensions.UnmarshalYAML(unmarshal, (*response)(r), &r.Extensions, nil); err != nil {
+		return err
+	}
+	if r.Ref != "" {
+		*r = Response{Ref: r.Ref}
+	}
+	return nil
+}
+
+// MarshalJSON - the parameter with its extensions, or only its reference
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	if p.Ref != "" {
+		return json.Marshal(Reference{Ref: p.Ref})
+	}
+	type parameter Parameter
+	return extensions.MarshalJSON(parameter(p), p.Extensions)
+}
+
+// UnmarshalJSON - read the parameter and its extensions
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type parameter Parameter
+	if err := extensions.UnmarshalJSON(data, (*parameter)(p), &p.Extensions, nil); err != nil {
+		return err
+	}
+	if p.Ref != "" {
+		*p = Parameter{Ref: p.Ref}
+	}
+	return nil
+}
+
+// MarshalYAML - the parameter with its extensions, or only its reference
+func (p Parameter) MarshalYAML() (interface{}, error) {
+	if p.Ref != "" {
+		return Reference{Ref: p.Ref}, nil
+	}
+	type parameter Parameter
+	return extensions.MarshalYAML(parameter(p), p.Extensions)
+}
+
+// UnmarshalYAML - read the parameter and its extensions
+func (p *Parameter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type parameter Parameter
+	if err := extensions.UnmarshalYAML(unmarshal, (*parameter)(p), &p.Extensions, nil); err != nil {
+		return err
+	}
+	if p.Ref != "" {
+		*p = Parameter{Ref: p.Ref}
+	}
+	return nil
+}
+
+// MarshalJSON - the schema with its extensions, or only its reference
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.Ref != "" {
+		return json.Marshal(Reference{Ref: s.Ref})
+	}
+	type schema Schema
+	return extensions.MarshalJSON(schema(s), s.Extensions)
+}
+
+// UnmarshalJSON - read the schema and its extensions
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	if err := extensions.UnmarshalJSON(data, (*schema)(s), &s.Extensions, nil); err != nil {
+		return err
+	}
+	if s.Ref != "" {
+		*s = Schema{Ref: s.Ref}
+	}
+	return nil
+}
+
+// MarshalYAML - the schema with its extensions, or only its reference
+func (s Schema) MarshalYAML() (interface{}, error) {
+	if s.Ref != "" {
+		return Reference{Ref: s.Ref}, nil
+	}
+	type schema Schema
+	return extensions.MarshalYAML(schema(s), s.Extensions)
+}
+
+// UnmarshalYAML - read the schema and its extensions
+func (s *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type schema Schema
+	if err := extensions.UnmarshalYAML(unmarshal, (*schema)(s), &s.Extensions, nil); err != nil {
+		return err
+	}
+	if s.Ref != "" {
+		*s = Schema{Ref: s.Ref}
+	}
+	return nil
+}
+
+// MarshalJSON - the info with its extensions
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return extensions.MarshalJSON(info(i), i.Extensions)
+}
+
+// UnmarshalJSON - read the info and its extensions
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type info Info
+	return extensions.UnmarshalJSON(data, (*info)(i), &i.Extensions, nil)
+}
+
+// MarshalYAML - the info with its extensions
+func (i Info) MarshalYAML() (interface{}, error) {
+	type info Info
+	return extensions.MarshalYAML(info(i), i.Extensions)
+}
+
+// UnmarshalYAML - read the info and its extensions
+func (i *Info) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type info Info
+	return extensions.UnmarshalYAML(unmarshal, (*info)(i), &i.Extensions, nil)
+}
@@ -24,6 +24,8 @@ type Tag struct {
 	Name         string                 `yaml:"name,omitempty" json:"name,omitempty"`
 	Description  string                 `yaml:"description,omitempty" json:"description,omitempty"`
 	ExternalDocs *ExternalDocumentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // SecurityScheme -
@@ -49,6 +51,8 @@ type Path struct {
 	HEAD       *Operation   `yaml:"head,omitempty" json:"head,omitempty"`
 	PATCH      *Operation   `yaml:"patch,omitempty" json:"patch,omitempty"`
 	Parameters []*Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Operation -
@@ -57,14 +61,16 @@ type Operation struct {
 	Summary      string                 `yaml:"summary,omitempty" json:"summary,omitempty"`
 	Description  string                 `yaml:"description,omitempty" json:"description,omitempty"`
 	ExternalDocs *ExternalDocumentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
-	OperationID  string                 `yaml:"operationID,omitempty" json:"operationID,omitempty"`
+	OperationID  string                 `yaml:"operationId,omitempty" json:"operationId,omitempty"`
 	Consumes     []string               `yaml:"consumes,omitempty" json:"consumes,omitempty"`
 	Produces     []string               `yaml:"produces,omitempty" json:"produces,omitempty"`
-	Parameters   map[string]*Parameter  `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Parameters   []*Parameter           `yaml:"parameters,omitempty" json:"parameters,omitempty"`
 	Responses    map[string]*Response   `yaml:"responses,omitempty" json:"responses,omitempty"`
 	Schemes      []string               `yaml:"schemes,omitempty" json:"schemes,omitempty"`
 	Deprecated   bool                   `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
-	Security     map[string][]string    `yaml:"security,omitempty" json:"security,omitempty"`
+	Security     []map[string][]string  `yaml:"security,omitempty" json:"security,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Response -
@@ -74,6 +80,8 @@ type Response struct {
 	Schema      *Schema                 `yaml:"schema,omitempty" json:"schema,omitempty"`
 	Headers     map[string]*Header      `yaml:"headers,omitempty" json:"headers,omitempty"`
 	Examples    map[string]*interface{} `yaml:"examples,omitempty" json:"examples,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Header -
@@ -96,9 +104,9 @@ type Parameter struct {
 	Items            *Items         `yaml:"items,omitempty" json:"items,omitempty"`
 	CollectionFormat string         `yaml:"collectionFormat,omitempty" json:"collectionFormat,omitempty"`
 	Default          *interface{}   `yaml:"default,omitempty" json:"default,omitempty"`
-	Maximum          int            `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	Maximum          float64        `yaml:"maximum,omitempty" json:"maximum,omitempty"`
 	ExclusiveMaximum bool           `yaml:"exclusiveMaximum,omitempty" json:"exclusiveMaximum,omitempty"`
-	Minimum          int            `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	Minimum          float64        `yaml:"minimum,omitempty" json:"minimum,omitempty"`
 	ExclusiveMinimum bool           `yaml:"exclusiveMinimum,omitempty" json:"exclusiveMinimum,omitempty"`
 	MaxLength        int            `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
 	MinLength        int            `yaml:"minLength,omitempty" json:"minLength,omitempty"`
@@ -107,7 +115,9 @@ type Parameter struct {
 	MinItems         int            `yaml:"minItems,omitempty" json:"minItems,omitempty"`
 	UniqueItems      bool           `yaml:"uniqueItems,omitempty" json:"uniqueItems,omitempty"`
 	Enum             []*interface{} `yaml:"enum,omitempty" json:"enum,omitempty"`
-	MultipleOf       int            `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
+	MultipleOf       float64        `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Items -
@@ -117,9 +127,9 @@ type Items struct {
 	Items            *Items         `yaml:"items,omitempty" json:"items,omitempty"`
 	CollectionFormat string         `yaml:"collectionFormat,omitempty" json:"collectionFormat,omitempty"`
 	Default          *interface{}   `yaml:"default,omitempty" json:"default,omitempty"`
-	Maximum          int            `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	Maximum          float64        `yaml:"maximum,omitempty" json:"maximum,omitempty"`
 	ExclusiveMaximum bool           `yaml:"exclusiveMaximum,omitempty" json:"exclusiveMaximum,omitempty"`
-	Minimum          int            `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	Minimum          float64        `yaml:"minimum,omitempty" json:"minimum,omitempty"`
 	ExclusiveMinimum bool           `yaml:"exclusiveMinimum,omitempty" json:"exclusiveMinimum,omitempty"`
 	MaxLength        int            `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
 	MinLength        int            `yaml:"minLength,omitempty" json:"minLength,omitempty"`
@@ -128,7 +138,7 @@ type Items struct {
 	MinItems         int            `yaml:"minItems,omitempty" json:"minItems,omitempty"`
 	UniqueItems      bool           `yaml:"uniqueItems,omitempty" json:"uniqueItems,omitempty"`
 	Enum             []*interface{} `yaml:"enum,omitempty" json:"enum,omitempty"`
-	MultipleOf       int            `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
+	MultipleOf       float64        `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
 }
 
 // Schema -
@@ -138,10 +148,10 @@ type Schema struct {
 	Title                string                 `yaml:"title,omitempty" json:"title,omitempty"`
 	Description          string                 `yaml:"description,omitempty" json:"description,omitempty"`
 	Default              *interface{}           `yaml:"default,omitempty" json:"default,omitempty"`
-	MultipleOf           int                    `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
-	Maximum              int                    `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	MultipleOf           float64                `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
+	Maximum              float64                `yaml:"maximum,omitempty" json:"maximum,omitempty"`
 	ExclusiveMaximum     bool                   `yaml:"exclusiveMaximum,omitempty" json:"exclusiveMaximum,omitempty"`
-	Minimum              int                    `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	Minimum              float64                `yaml:"minimum,omitempty" json:"minimum,omitempty"`
 	ExclusiveMinimum     bool                   `yaml:"exclusiveMinimum,omitempty" json:"exclusiveMinimum,omitempty"`
 	MaxLength            int                    `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
 	MinLength            int                    `yaml:"minLength,omitempty" json:"minLength,omitempty"`
@@ -154,15 +164,26 @@ type Schema struct {
 	Required             []string               `yaml:"required,omitempty" json:"required,omitempty"`
 	Enum                 []string               `yaml:"enum,omitempty" json:"enum,omitempty"`
 	Type                 string                 `yaml:"type,omitempty" json:"type,omitempty"`
-	Items                []*Schema              `yaml:"items,omitempty" json:"items,omitempty"`
+	Items                *Schema                `yaml:"items,omitempty" json:"items,omitempty"`
 	AllOf                []*Schema              `yaml:"allOf,omitempty" json:"allOf,omitempty"`
 	Properties           map[string]*Schema     `yaml:"properties,omitempty" json:"properties,omitempty"`
 	AdditionalProperties *Schema                `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
 	Discriminator        string                 `yaml:"discriminator,omitempty" json:"discriminator,omitempty"`
 	ReadOnly             bool                   `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
-	XML                  string                 `yaml:"xml,omitempty" json:"xml,omitempty"`
+	XML                  *XML                   `yaml:"xml,omitempty" json:"xml,omitempty"`
 	ExternalDocs         *ExternalDocumentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
 	Example              *interface{}           `yaml:"example,omitempty" json:"example,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
+}
+
+// XML -
+type XML struct {
+	Name      string `yaml:"name,omitempty" json:"name,omitempty"`
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	Prefix    string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Attribute bool   `yaml:"attribute,omitempty" json:"attribute,omitempty"`
+	Wrapped   bool   `yaml:"wrapped,omitempty" json:"wrapped,omitempty"`
 }
 
 // ExternalDocumentation -
@@ -179,6 +200,8 @@ type Info struct {
 	Contact        *Contact `yaml:"contact,omitempty" json:"contact,omitempty"`
 	License        *License `yaml:"license,omitempty" json:"license,omitempty"`
 	Version        string   `yaml:"version,omitempty" json:"version,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Contact -
@@ -191,5 +214,5 @@ type Contact struct {
 // License -
 type License struct {
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
-	URL  string `yaml:"URL,omitempty" json:"URL,omitempty"`
+	URL  string `yaml:"url,omitempty" json:"url,omitempty"`
 }
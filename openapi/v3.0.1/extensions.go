@@ -0,0 +1,163 @@
+package openapi
+
+import "github.com/rancher/gen-api-docs/openapi/extensions"
+
+// The extensions with a type of their own, every other x- field is read as
+// plain JSON values
+const (
+	codeSamplesExtension      = "x-codeSamples"
+	websocketExtension        = "x-websocket"
+	actionsExtension          = "x-rancher-actions"
+	groupVersionKindExtension = "x-kubernetes-group-version-kind"
+	driverExtension           = "x-rancher-driver"
+	referenceExtension        = "x-rancher-reference"
+	rancherTypeExtension      = "x-rancher-type"
+)
+
+// operationExtensions - the extensions of an operation read as their type
+var operationExtensions = extensions.Types{
+	codeSamplesExtension: []CodeSample{},
+	websocketExtension:   &Websocket{},
+	actionsExtension:     map[string]Action{},
+}
+
+// schemaExtensions - the extensions of a schema read as their type,
+// along with the norman metadata translateSchema records
+var schemaExtensions = extensions.Types{
+	groupVersionKindExtension:      []GroupVersionKind{},
+	driverExtension:                &Driver{},
+	referenceExtension:             &ResourceReference{},
+	rancherTypeExtension:           "",
+	"x-rancher-code-name":          "",
+	"x-rancher-dynamic-field":      false,
+	"x-rancher-plural-name":        "",
+	"x-rancher-resource-methods":   []string{},
+	"x-rancher-collection-methods": []string{},
+}
+
+// Action - x-rancher-actions, what an action of a POST takes and returns.
+// The request body and response of the operation are oneOf every action's.
+type Action struct {
+	Description string  `yaml:"description,omitempty" json:"description,omitempty"`
+	Input       *Schema `yaml:"input,omitempty" json:"input,omitempty"`
+	Output      *Schema `yaml:"output,omitempty" json:"output,omitempty"`
+}
+
+// Websocket - x-websocket, the messages exchanged once an operation upgraded
+// the connection. OpenAPI can't describe them, the asyncapi export does.
+type Websocket struct {
+	// Query - the query parameters that open the websocket, e.g. shell=true
+	// on the GET of a cluster
+	Query        map[string]string `yaml:"query,omitempty" json:"query,omitempty"`
+	Subprotocols []string          `yaml:"subprotocols,omitempty" json:"subprotocols,omitempty"`
+	// Receive - messages sent by the server
+	Receive []Message `yaml:"receive,omitempty" json:"receive,omitempty"`
+	// Send - messages sent by the client
+	Send []Message `yaml:"send,omitempty" json:"send,omitempty"`
+}
+
+// Message - a websocket message
+type Message struct {
+	Name    string  `yaml:"name" json:"name"`
+	Summary string  `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Payload *Schema `yaml:"payload,omitempty" json:"payload,omitempty"`
+}
+
+// CodeSample - x-codeSamples, https://redocly.com/docs/api-reference-docs/specification-extensions/x-code-samples/
+type CodeSample struct {
+	Lang   string `yaml:"lang" json:"lang"`
+	Label  string `yaml:"label,omitempty" json:"label,omitempty"`
+	Source string `yaml:"source" json:"source"`
+}
+
+// GroupVersionKind - x-kubernetes-group-version-kind, the Kubernetes kind a schema describes, as in the Kubernetes OpenAPI docs
+type GroupVersionKind struct {
+	Group   string `yaml:"group" json:"group"`
+	Version string `yaml:"version" json:"version"`
+	Kind    string `yaml:"kind" json:"kind"`
+}
+
+// Driver - x-rancher-driver, the Rancher driver a dynamic schema is generated from, only there while the driver is active
+type Driver struct {
+	Name string `yaml:"name" json:"name"`
+	Kind string `yaml:"kind,omitempty" json:"kind,omitempty"`
+}
+
+// ResourceReference - x-rancher-reference, the resource a reference field holds the id of
+type ResourceReference struct {
+	Schema string `yaml:"schema" json:"schema"`
+	Path   string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// CodeSamples - x-codeSamples of the operation
+func (o Operation) CodeSamples() []CodeSample {
+	samples, _ := o.Extensions[codeSamplesExtension].([]CodeSample)
+	return samples
+}
+
+// SetCodeSamples - set x-codeSamples, none removes it
+func (o *Operation) SetCodeSamples(samples []CodeSample) {
+	o.Extensions = o.Extensions.With(codeSamplesExtension, samples)
+}
+
+// Websocket - x-websocket, set when the operation upgrades the connection to a websocket
+func (o Operation) Websocket() *Websocket {
+	ws, _ := o.Extensions[websocketExtension].(*Websocket)
+	return ws
+}
+
+// SetWebsocket - set x-websocket, nil removes it
+func (o *Operation) SetWebsocket(ws *Websocket) {
+	o.Extensions = o.Extensions.With(websocketExtension, ws)
+}
+
+// Actions - x-rancher-actions, the Rancher actions the operation performs,
+// by the value of its action query parameter
+func (o Operation) Actions() map[string]Action {
+	actions, _ := o.Extensions[actionsExtension].(map[string]Action)
+	return actions
+}
+
+// SetActions - set x-rancher-actions, none removes it
+func (o *Operation) SetActions(actions map[string]Action) {
+	o.Extensions = o.Extensions.With(actionsExtension, actions)
+}
+
+// GroupVersionKind - x-kubernetes-group-version-kind of the schema
+func (s Schema) GroupVersionKind() []GroupVersionKind {
+	gvk, _ := s.Extensions[groupVersionKindExtension].([]GroupVersionKind)
+	return gvk
+}
+
+// SetGroupVersionKind - set x-kubernetes-group-version-kind, none removes it
+func (s *Schema) SetGroupVersionKind(gvk []GroupVersionKind) {
+	s.Extensions = s.Extensions.With(groupVersionKindExtension, gvk)
+}
+
+// Driver - x-rancher-driver of the schema
+func (s Schema) Driver() *Driver {
+	driver, _ := s.Extensions[driverExtension].(*Driver)
+	return driver
+}
+
+// SetDriver - set x-rancher-driver, nil removes it
+func (s *Schema) SetDriver(driver *Driver) {
+	s.Extensions = s.Extensions.With(driverExtension, driver)
+}
+
+// Reference - x-rancher-reference of the schema
+func (s Schema) Reference() *ResourceReference {
+	reference, _ := s.Extensions[referenceExtension].(*ResourceReference)
+	return reference
+}
+
+// SetReference - set x-rancher-reference, nil removes it
+func (s *Schema) SetReference(reference *ResourceReference) {
+	s.Extensions = s.Extensions.With(referenceExtension, reference)
+}
+
+// RancherType - x-rancher-type, the norman type of a field, e.g. reference[project]
+func (s Schema) RancherType() string {
+	typ, _ := s.Extensions[rancherTypeExtension].(string)
+	return typ
+}
@@ -0,0 +1,477 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/rancher/gen-api-docs/openapi/extensions"
+)
+
+// Extensions - the x- fields of an object, marshalled inline with its other fields
+type Extensions = extensions.Extensions
+
+// Reference - https://swagger.io/specification/#referenceObject
+// An object with a $ref is only the reference. A schema with other fields
+// along with its $ref is marshalled as an allOf of the reference, any other
+// object fails to marshal or be read.
+type Reference struct {
+	Ref string `yaml:"$ref" json:"$ref"`
+}
+
+// siblings - a field of object, a struct with a Ref, is set along with the Ref
+func siblings(object interface{}) bool {
+	v := reflect.ValueOf(object)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Name == "Ref" {
+			continue
+		}
+		field := v.Field(i)
+		if !reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyRef - an error when object has other fields along with ref
+func onlyRef(ref string, object interface{}) error {
+	if ref != "" && siblings(object) {
+		return fmt.Errorf("Reference %s has other fields, OpenAPI 3.0 only allows the $ref", ref)
+	}
+	return nil
+}
+
+// refAllOf - a schema with other fields along with its $ref, like nullable or
+// a description, as an allOf of the reference with those fields
+func refAllOf(s Schema) Schema {
+	if s.Ref == "" || !siblings(s) {
+		return s
+	}
+	s.AllOf = append([]Schema{{Ref: s.Ref}}, s.AllOf...)
+	s.Ref = ""
+	return s
+}
+
+// allOfRef - a schema that is an allOf of only a reference as the $ref with
+// the other fields of the schema, how refAllOf marshals it
+func allOfRef(s Schema) Schema {
+	if s.Ref != "" || len(s.AllOf) != 1 || s.AllOf[0].Ref == "" || siblings(s.AllOf[0]) {
+		return s
+	}
+	s.Ref = s.AllOf[0].Ref
+	s.AllOf = nil
+	return s
+}
+
+// MarshalJSON - the info with its extensions
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return extensions.MarshalJSON(info(i), i.Extensions)
+}
+
+// UnmarshalJSON - read the info and its extensions
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type info Info
+	return extensions.UnmarshalJSON(data, (*info)(i), &i.Extensions, nil)
+}
+
+// MarshalYAML - the info with its extensions
+func (i Info) MarshalYAML() (interface{}, error) {
+	type info Info
+	return extensions.MarshalYAML(info(i), i.Extensions)
+}
+
+// UnmarshalYAML - read the info and its extensions
+func (i *Info) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type info Info
+	return extensions.UnmarshalYAML(unmarshal, (*info)(i), &i.Extensions, nil)
+}
+
+// MarshalJSON - the path item with its extensions
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	type pathItem PathItem
+	return extensions.MarshalJSON(pathItem(p), p.Extensions)
+}
+
+// UnmarshalJSON - read the path item and its extensions
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	type pathItem PathItem
+	return extensions.UnmarshalJSON(data, (*pathItem)(p), &p.Extensions, nil)
+}
+
+// MarshalYAML - the path item with its extensions
+func (p PathItem) MarshalYAML() (interface{}, error) {
+	type pathItem PathItem
+	return extensions.MarshalYAML(pathItem(p), p.Extensions)
+}
+
+// UnmarshalYAML - read the path item and its extensions
+func (p *PathItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type pathItem PathItem
+	return extensions.UnmarshalYAML(unmarshal, (*pathItem)(p), &p.Extensions, nil)
+}
+
+// MarshalJSON - the components with its extensions
+func (c Components) MarshalJSON() ([]byte, error) {
+	type components Components
+	return extensions.MarshalJSON(components(c), c.Extensions)
+}
+
+// UnmarshalJSON - read the components and its extensions
+func (c *Components) UnmarshalJSON(data []byte) error {
+	type components Components
+	return extensions.UnmarshalJSON(data, (*components)(c), &c.Extensions, nil)
+}
+
+// MarshalYAML - the components with its extensions
+func (c Components) MarshalYAML() (interface{}, error) {
+	type components Components
+	return extensions.MarshalYAML(components(c), c.Extensions)
+}
+
+// UnmarshalYAML - read the components and its extensions
+func (c *Components) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type components Components
+	return extensions.UnmarshalYAML(unmarshal, (*components)(c), &c.Extensions, nil)
+}
+
+// MarshalJSON - the parameter with its extensions, or only its reference
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	if p.Ref != "" {
+		if err := onlyRef(p.Ref, p); err != nil {
+			return nil, err
+		}
+		return json.Marshal(Reference{Ref: p.Ref})
+	}
+	type parameter Parameter
+	return extensions.MarshalJSON(parameter(p), p.Extensions)
+}
+
+// UnmarshalJSON - read the parameter and its extensions
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type parameter Parameter
+	if err := extensions.UnmarshalJSON(data, (*parameter)(p), &p.Extensions, nil); err != nil {
+		return err
+	}
+	return onlyRef(p.Ref, *p)
+}
+
+// MarshalYAML - the parameter with its extensions, or only its reference
+func (p Parameter) MarshalYAML() (interface{}, error) {
+	if p.Ref != "" {
+		if err := onlyRef(p.Ref, p); err != nil {
+			return nil, err
+		}
+		return Reference{Ref: p.Ref}, nil
+	}
+	type parameter Parameter
+	return extensions.MarshalYAML(parameter(p), p.Extensions)
+}
+
+// UnmarshalYAML - read the parameter and its extensions
+func (p *Parameter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type parameter Parameter
+	if err := extensions.UnmarshalYAML(unmarshal, (*parameter)(p), &p.Extensions, nil); err != nil {
+		return err
+	}
+	return onlyRef(p.Ref, *p)
+}
+
+// MarshalJSON - the operation with its extensions
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return extensions.MarshalJSON(operation(o), o.Extensions)
+}
+
+// UnmarshalJSON - read the operation and its extensions
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type operation Operation
+	return extensions.UnmarshalJSON(data, (*operation)(o), &o.Extensions, operationExtensions)
+}
+
+// MarshalYAML - the operation with its extensions
+func (o Operation) MarshalYAML() (interface{}, error) {
+	type operation Operation
+	return extensions.MarshalYAML(operation(o), o.Extensions)
+}
+
+// UnmarshalYAML - read the operation and its extensions
+func (o *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type operation Operation
+	return extensions.UnmarshalYAML(unmarshal, (*operation)(o), &o.Extensions, operationExtensions)
+}
+
+// MarshalJSON - the schema with its extensions, a $ref with other fields as an allOf
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.Ref != "" && !siblings(s) {
+		return json.Marshal(Reference{Ref: s.Ref})
+	}
+	type schema Schema
+	s = refAllOf(s)
+	return extensions.MarshalJSON(schema(s), s.Extensions)
+}
+
+// UnmarshalJSON - read the schema and its extensions
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	if err := extensions.UnmarshalJSON(data, (*schema)(s), &s.Extensions, schemaExtensions); err != nil {
+		return err
+	}
+	*s = allOfRef(*s)
+	return nil
+}
+
+// MarshalYAML - the schema with its extensions, a $ref with other fields as an allOf
+func (s Schema) MarshalYAML() (interface{}, error) {
+	if s.Ref != "" && !siblings(s) {
+		return Reference{Ref: s.Ref}, nil
+	}
+	type schema Schema
+	s = refAllOf(s)
+	return extensions.MarshalYAML(schema(s), s.Extensions)
+}
+
+// UnmarshalYAML - read the schema and its extensions
+func (s *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type schema Schema
+	if err := extensions.UnmarshalYAML(unmarshal, (*schema)(s), &s.Extensions, schemaExtensions); err != nil {
+		return err
+	}
+	*s = allOfRef(*s)
+	return nil
+}
+
+// MarshalJSON - the response with its extensions, or only its reference
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Ref != "" {
+		if err := onlyRef(r.Ref, r); err != nil {
+			return nil, err
+		}
+		return json.Marshal(Reference{Ref: r.Ref})
+	}
+	type response Response
+	return extensions.MarshalJSON(response(r), r.Extensions)
+}
+
+// UnmarshalJSON - read the response and its extensions
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	if err := extensions.UnmarshalJSON(data, (*response)(r), &r.Extensions, nil); err != nil {
+		return err
+	}
+	return onlyRef(r.Ref, *r)
+}
+
+// MarshalYAML - the response with its extensions, or only its reference
+func (r Response) MarshalYAML() (interface{}, error) {
+	if r.Ref != "" {
+		if err := onlyRef(r.Ref, r); err != nil {
+			return nil, err
+		}
+		return Reference{Ref: r.Ref}, nil
+	}
+	type response Response
+	return extensions.MarshalYAML(response(r), r.Extensions)
+}
+
+// UnmarshalYAML - read the response and its extensions
+func (r *Response) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type response Response
+	if err := extensions.UnmarshalYAML(unmarshal, (*response)(r), &r.Extensions, nil); err != nil {
+		return err
+	}
+	return onlyRef(r.Ref, *r)
+}
+
+// MarshalJSON - the tag with its extensions
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type tag Tag
+	return extensions.MarshalJSON(tag(t), t.Extensions)
+}
+
+// UnmarshalJSON - read the tag and its extensions
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type tag Tag
+	return extensions.UnmarshalJSON(data, (*tag)(t), &t.Extensions, nil)
+}
+
+// MarshalYAML - the tag with its extensions
+func (t Tag) MarshalYAML() (interface{}, error) {
+	type tag Tag
+	return extensions.MarshalYAML(tag(t), t.Extensions)
+}
+
+// UnmarshalYAML - read the tag and its extensions
+func (t *Tag) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type tag Tag
+	return extensions.UnmarshalYAML(unmarshal, (*tag)(t), &t.Extensions, nil)
+}
+
+// MarshalJSON - the security scheme, or only its reference
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	if s.Ref != "" {
+		if err := onlyRef(s.Ref, s); err != nil {
+			return nil, err
+		}
+		return json.Marshal(Reference{Ref: s.Ref})
+	}
+	type securityScheme SecurityScheme
+	return json.Marshal(securityScheme(s))
+}
+
+// UnmarshalJSON - read the security scheme
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	type securityScheme SecurityScheme
+	if err := json.Unmarshal(data, (*securityScheme)(s)); err != nil {
+		return err
+	}
+	return onlyRef(s.Ref, *s)
+}
+
+// MarshalYAML - the security scheme, or only its reference
+func (s SecurityScheme) MarshalYAML() (interface{}, error) {
+	if s.Ref != "" {
+		if err := onlyRef(s.Ref, s); err != nil {
+			return nil, err
+		}
+		return Reference{Ref: s.Ref}, nil
+	}
+	type securityScheme SecurityScheme
+	return securityScheme(s), nil
+}
+
+// UnmarshalYAML - read the security scheme
+func (s *SecurityScheme) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type securityScheme SecurityScheme
+	if err := unmarshal((*securityScheme)(s)); err != nil {
+		return err
+	}
+	return onlyRef(s.Ref, *s)
+}
+
+// MarshalJSON - the request body, or only its reference
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	if r.Ref != "" {
+		if err := onlyRef(r.Ref, r); err != nil {
+			return nil, err
+		}
+		return json.Marshal(Reference{Ref: r.Ref})
+	}
+	type requestBody RequestBody
+	return json.Marshal(requestBody(r))
+}
+
+// UnmarshalJSON - read the request body
+func (r *RequestBody) UnmarshalJSON(data []byte) error {
+	type requestBody RequestBody
+	if err := json.Unmarshal(data, (*requestBody)(r)); err != nil {
+		return err
+	}
+	return onlyRef(r.Ref, *r)
+}
+
+// MarshalYAML - the request body, or only its reference
+func (r RequestBody) MarshalYAML() (interface{}, error) {
+	if r.Ref != "" {
+		if err := onlyRef(r.Ref, r); err != nil {
+			return nil, err
+		}
+		return Reference{Ref: r.Ref}, nil
+	}
+	type requestBody RequestBody
+	return requestBody(r), nil
+}
+
+// UnmarshalYAML - read the request body
+func (r *RequestBody) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type requestBody RequestBody
+	if err := unmarshal((*requestBody)(r)); err != nil {
+		return err
+	}
+	return onlyRef(r.Ref, *r)
+}
+
+// MarshalJSON - the example, or only its reference
+func (e Example) MarshalJSON() ([]byte, error) {
+	if e.Ref != "" {
+		if err := onlyRef(e.Ref, e); err != nil {
+			return nil, err
+		}
+		return json.Marshal(Reference{Ref: e.Ref})
+	}
+	type example Example
+	return json.Marshal(example(e))
+}
+
+// UnmarshalJSON - read the example
+func (e *Example) UnmarshalJSON(data []byte) error {
+	type example Example
+	if err := json.Unmarshal(data, (*example)(e)); err != nil {
+		return err
+	}
+	return onlyRef(e.Ref, *e)
+}
+
+// MarshalYAML - the example, or only its reference
+func (e Example) MarshalYAML() (interface{}, error) {
+	if e.Ref != "" {
+		if err := onlyRef(e.Ref, e); err != nil {
+			return nil, err
+		}
+		return Reference{Ref: e.Ref}, nil
+	}
+	type example Example
+	return example(e), nil
+}
+
+// UnmarshalYAML - read the example
+func (e *Example) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type example Example
+	if err := unmarshal((*example)(e)); err != nil {
+		return err
+	}
+	return onlyRef(e.Ref, *e)
+}
+
+// MarshalJSON - the link, or only its reference
+func (l Link) MarshalJSON() ([]byte, error) {
+	if l.Ref != "" {
+		if err := onlyRef(l.Ref, l); err != nil {
+			return nil, err
+		}
+		return json.Marshal(Reference{Ref: l.Ref})
+	}
+	type link Link
+	return json.Marshal(link(l))
+}
+
+// UnmarshalJSON - read the link
+func (l *Link) UnmarshalJSON(data []byte) error {
+	type link Link
+	if err := json.Unmarshal(data, (*link)(l)); err != nil {
+		return err
+	}
+	return onlyRef(l.Ref, *l)
+}
+
+// MarshalYAML - the link, or only its reference
+func (l Link) MarshalYAML() (interface{}, error) {
+	if l.Ref != "" {
+		if err := onlyRef(l.Ref, l); err != nil {
+			return nil, err
+		}
+		return Reference{Ref: l.Ref}, nil
+	}
+	type link Link
+	return link(l), nil
+}
+
+// UnmarshalYAML - read the link
+func (l *Link) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type link Link
+	if err := unmarshal((*link)(l)); err != nil {
+		return err
+	}
+	return onlyRef(l.Ref, *l)
+}
@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestNullableRef(t *testing.T) {
+	nullable := Schema{
+		Ref:         "#/components/schemas/cluster",
+		Description: "The cluster",
+		Nullable:    true,
+	}
+
+	out, err := json.Marshal(nullable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"description":"The cluster","allOf":[{"$ref":"#/components/schemas/cluster"}],"nullable":true}`; string(out) != expected {
+		t.Errorf("expected the $ref in an allOf next to its fields, got %s", out)
+	}
+	read := Schema{}
+	if err := json.Unmarshal(out, &read); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, nullable) {
+		t.Errorf("expected %+v after a JSON round trip, got %+v", nullable, read)
+	}
+
+	yamlOut, err := yaml.Marshal(nullable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read = Schema{}
+	if err := yaml.Unmarshal(yamlOut, &read); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, nullable) {
+		t.Errorf("expected %+v after a YAML round trip, got %+v", nullable, read)
+	}
+
+	if out, err := json.Marshal(Schema{Ref: "#/components/schemas/cluster"}); err != nil || string(out) != `{"$ref":"#/components/schemas/cluster"}` {
+		t.Errorf("expected only the $ref, got %s %v", out, err)
+	}
+}
+
+func TestRefSiblings(t *testing.T) {
+	param := Parameter{Ref: "#/components/parameters/limit", Description: "Page size"}
+	if _, err := json.Marshal(param); err == nil {
+		t.Errorf("expected an error marshalling a parameter $ref with a description")
+	}
+	if _, err := yaml.Marshal(Response{Ref: "#/components/responses/error", Description: "Error"}); err == nil {
+		t.Errorf("expected an error marshalling a response $ref with a description")
+	}
+
+	read := Parameter{}
+	if err := json.Unmarshal([]byte(`{"$ref":"#/components/parameters/limit","description":"Page size"}`), &read); err == nil {
+		t.Errorf("expected an error reading a parameter $ref with a description, got %+v", read)
+	}
+	read = Parameter{}
+	if err := json.Unmarshal([]byte(`{"$ref":"#/components/parameters/limit"}`), &read); err != nil || read.Ref != "#/components/parameters/limit" {
+		t.Errorf("expected the reference, got %+v %v", read, err)
+	}
+}
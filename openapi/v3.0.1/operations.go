@@ -0,0 +1,102 @@
+package openapi
+
+import "strings"
+
+// Methods - the methods of a path item, in the order they are documented
+var Methods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "TRACE"}
+
+// Operation - the operation for method, nil when the path item has none
+func (p PathItem) Operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "DELETE":
+		return p.Delete
+	case "PATCH":
+		return p.Patch
+	case "HEAD":
+		return p.Head
+	case "OPTIONS":
+		return p.Options
+	case "TRACE":
+		return p.Trace
+	}
+	return nil
+}
+
+// Operations - the operations of the path item by method
+func (p PathItem) Operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for _, method := range Methods {
+		if op := p.Operation(method); op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// Parameter - the component a parameter references, or the parameter itself
+// when it isn't a reference or the component is missing
+func (o *OpenAPI) Parameter(param Parameter) Parameter {
+	if param.Ref == "" {
+		return param
+	}
+	name := strings.TrimPrefix(param.Ref, "#/components/parameters/")
+	if resolved, ok := o.Components.Parameters[name]; ok {
+		return resolved
+	}
+	return param
+}
+
+// Parameters - the parameters of op on pathItem, the path item's first,
+// with their references resolved
+func (o *OpenAPI) Parameters(pathItem PathItem, op *Operation) []Parameter {
+	params := make([]Parameter, 0, len(pathItem.Parameters)+len(op.Parameters))
+	for _, param := range append(append([]Parameter{}, pathItem.Parameters...), op.Parameters...) {
+		params = append(params, o.Parameter(param))
+	}
+	return params
+}
+
+// ActionRequired - the operation only performs its actions, a POST with
+// actions that creates without one doesn't require the action parameter
+func (o Operation) ActionRequired() bool {
+	for _, param := range o.Parameters {
+		if param.In == "query" && param.Name == "action" {
+			return param.Required
+		}
+	}
+	return len(o.Actions()) > 0
+}
+
+// Selects - the query parameter picks an action or opens the websocket of
+// the operation, its value is set by each action or the websocket's query
+func (o Operation) Selects(param Parameter) bool {
+	if param.In != "query" {
+		return false
+	}
+	if len(o.Actions()) > 0 && param.Name == "action" {
+		return true
+	}
+	ws := o.Websocket()
+	return ws != nil && ws.Query[param.Name] != ""
+}
+
+// Upgrades - every request of the operation opens its websocket. A GET that
+// only opens it with an optional query, like shell=true, is a plain GET too.
+func (o Operation) Upgrades() bool {
+	ws := o.Websocket()
+	if ws == nil {
+		return false
+	}
+	for _, param := range o.Parameters {
+		if _, ok := ws.Query[param.Name]; ok && param.In == "query" {
+			return param.Required
+		}
+	}
+	return true
+}
@@ -18,6 +18,8 @@ type Info struct {
 	Title       string `yaml:"title,omitempty" json:"title,omitempty"`
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 	Version     string `yaml:"version,omitempty" json:"version,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Server - https://swagger.io/specification/#serverObject
@@ -42,6 +44,8 @@ type PathItem struct {
 	Trace       *Operation  `yaml:"trace,omitempty" json:"trace,omitempty"`
 	Servers     []Server    `yaml:"servers,omitempty" json:"servers,omitempty"`
 	Parameters  []Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"` // or Ref
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Components - https://swagger.io/specification/#componentsObject
@@ -55,6 +59,8 @@ type Components struct {
 	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes,omitempty" json:"securitySchemes,omitempty"` // or Ref
 	Links           map[string]Link           `yaml:"links,omitempty" json:"links,omitempty"`                     // or Ref
 	Callbacks       map[string]PathItem       `yaml:"callbacks,omitempty" json:"callbacks,omitempty"`             // or Ref
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // SecurityScheme - https://swagger.io/specification/#securitySchemeObject
@@ -104,6 +110,8 @@ type Parameter struct {
 	Example         *interface{}         `yaml:"example,omitempty" json:"example,omitempty"`
 	Examples        map[string]Example   `yaml:"examples,omitempty" json:"examples,omitempty"` // or ref
 	Content         map[string]MediaType `yaml:"content,omitempty" json:"content,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // RequestBody - https://swagger.io/specification/#requestBodyObject
@@ -152,30 +160,33 @@ type Operation struct {
 	Responses    map[string]Response    `yaml:"responses,omitempty" json:"responses,omitempty"`
 	Callbacks    map[string]PathItem    `yaml:"callbacks,omitempty" json:"callbacks,omitempty"`
 	Deprecated   bool                   `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
-	Security     map[string][]string    `yaml:"security,omitempty" json:"security,omitempty"`
-	Servers      []Server               `yaml:"servers,omitempty" json:"servers,omitempty"`
+	// Security - nil uses the document's security, an empty list marks the operation unauthenticated
+	Security *[]map[string][]string `yaml:"security,omitempty" json:"security,omitempty"`
+	Servers  []Server               `yaml:"servers,omitempty" json:"servers,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Schema - https://swagger.io/specification/#schemaObject
 type Schema struct {
 	Ref string `yaml:"$ref,omitempty" json:"$ref,omitempty"`
 	// JSON Schema Fields
-	Title            string   `yaml:"title,omitempty" json:"title,omitempty"`
-	MultipleOf       *int64   `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
-	Maximum          *int64   `yaml:"maximum,omitempty" json:"maximum,omitempty"`
-	ExclusiveMaximum *int64   `yaml:"exclusiveMaximum,omitempty" json:"exclusiveMaximum,omitempty"`
-	Minimum          *int64   `yaml:"minimum,omitempty" json:"minimum,omitempty"`
-	ExclusiveMinimum *int64   `yaml:"exclusiveMinimum,omitempty" json:"exclusiveMinimum,omitempty"`
-	MaxLength        *int64   `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
-	MinLength        *int64   `yaml:"minLength,omitempty" json:"minLength,omitempty"`
-	Pattern          string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
-	MaxItems         *int64   `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
-	MinItems         *int64   `yaml:"minItems,omitempty" json:"minItems,omitempty"`
-	UniqueItems      bool     `yaml:"uniqueItems,omitempty" json:"uniqueItems,omitempty"`
-	MaxProperties    *int64   `yaml:"maxProperties,omitempty" json:"maxProperties,omitempty"`
-	MinProperties    *int64   `yaml:"minProperties,omitempty" json:"minProperties,omitempty"`
-	Required         []string `yaml:"required,omitempty" json:"required,omitempty"`
-	Enum             []string `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Title            string        `yaml:"title,omitempty" json:"title,omitempty"`
+	MultipleOf       *float64      `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
+	Maximum          *float64      `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	ExclusiveMaximum bool          `yaml:"exclusiveMaximum,omitempty" json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64      `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	ExclusiveMinimum bool          `yaml:"exclusiveMinimum,omitempty" json:"exclusiveMinimum,omitempty"`
+	MaxLength        *int64        `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+	MinLength        *int64        `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	Pattern          string        `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	MaxItems         *int64        `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+	MinItems         *int64        `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	UniqueItems      bool          `yaml:"uniqueItems,omitempty" json:"uniqueItems,omitempty"`
+	MaxProperties    *int64        `yaml:"maxProperties,omitempty" json:"maxProperties,omitempty"`
+	MinProperties    *int64        `yaml:"minProperties,omitempty" json:"minProperties,omitempty"`
+	Required         []string      `yaml:"required,omitempty" json:"required,omitempty"`
+	Enum             []interface{} `yaml:"enum,omitempty" json:"enum,omitempty"`
 
 	// OpenAPI modified JSON Schema Fields
 	Type                 string            `yaml:"type,omitempty" json:"type,omitempty"`
@@ -188,7 +199,7 @@ type Schema struct {
 	AllOf                []Schema          `yaml:"allOf,omitempty" json:"allOf,omitempty"`
 	OneOf                []Schema          `yaml:"oneOf,omitempty" json:"oneOf,omitempty"`
 	AnyOf                []Schema          `yaml:"anyOf,omitempty" json:"anyOf,omitempty"`
-	Not                  []Schema          `yaml:"not,omitempty" json:"not,omitempty"`
+	Not                  *Schema           `yaml:"not,omitempty" json:"not,omitempty"`
 
 	// Static OpenAPI Fields
 	Discriminator *Discriminator         `yaml:"discriminator,omitempty" json:"discriminator,omitempty"`
@@ -199,6 +210,8 @@ type Schema struct {
 	ExternalDocs  *ExternalDocumentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
 	Example       interface{}            `yaml:"example,omitempty" json:"example,omitempty"`
 	Deprecated    bool                   `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Discriminator - https://swagger.io/specification/#discriminatorObject
@@ -217,22 +230,22 @@ type XML struct {
 }
 
 // Response - https://swagger.io/specification/#responseObject
-// if this is an "or $ref" we may need to mark all the fields omitempty
 type Response struct {
 	Ref         string               `yaml:"$ref,omitempty" json:"$ref,omitempty"`
 	Description string               `yaml:"description,omitempty" json:"description,omitempty"`
 	Headers     map[string]Parameter `yaml:"headers,omitempty" json:"headers,omitempty"`
 	Content     map[string]MediaType `yaml:"content,omitempty" json:"content,omitempty"`
 	Links       map[string]Link      `yaml:"links,omitempty" json:"links,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // Link - https://swagger.io/specification/#linkObject
-// if this is an "or $ref" we may need to mark all the fields omitempty
 // expression: https://swagger.io/specification/#runtimeExpression
 type Link struct {
 	Ref          string            `yaml:"$ref,omitempty" json:"$ref,omitempty"`
 	OperationRef string            `yaml:"operationRef,omitempty" json:"operationRef,omitempty"`
-	OperationID  string            `yaml:"operationID,omitempty" json:"operationID,omitempty"`
+	OperationID  string            `yaml:"operationId,omitempty" json:"operationId,omitempty"`
 	Parameters   map[string]string `yaml:"parameters,omitempty" json:"parameters,omitempty"`   //expression
 	RequestBody  string            `yaml:"requestBody,omitempty" json:"requestBody,omitempty"` // expression
 	Description  string            `yaml:"description,omitempty" json:"description,omitempty"`
@@ -244,6 +257,8 @@ type Tag struct {
 	Name         string                 `yaml:"name" json:"name"`
 	Description  string                 `yaml:"description,omitempty" json:"description,omitempty"`
 	ExternalDocs *ExternalDocumentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
+
+	Extensions Extensions `yaml:"-" json:"-"`
 }
 
 // ExternalDocumentation - https://swagger.io/specification/#externalDocumentationObject
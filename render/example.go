@@ -0,0 +1,88 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+// example - JSON example of a component schema
+func example(swagger *openapi.OpenAPI, name string) string {
+	schema, ok := swagger.Components.Schemas[name]
+	if !ok {
+		return ""
+	}
+	out, err := json.MarshalIndent(Example(swagger, schema, false), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// Example - example value of a schema built from examples, defaults, enums and types.
+// Request examples leave out read only properties.
+func Example(swagger *openapi.OpenAPI, schema openapi.Schema, request bool) interface{} {
+	return exampleValue(swagger, schema, request, map[string]bool{})
+}
+
+func exampleValue(swagger *openapi.OpenAPI, schema openapi.Schema, request bool, seen map[string]bool) interface{} {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, schemaPrefix)
+		if seen[name] {
+			return map[string]interface{}{}
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return exampleValue(swagger, swagger.Components.Schemas[name], request, seen)
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.OneOf) > 0:
+		return exampleValue(swagger, schema.OneOf[0], request, seen)
+	}
+
+	switch schema.Type {
+	case "string":
+		if schema.Format == "date-time" {
+			return "2018-01-01T00:00:00Z"
+		}
+		return "string"
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return false
+	case "array":
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{exampleValue(swagger, *schema.Items, request, seen)}
+	}
+
+	obj := make(map[string]interface{})
+	for _, part := range schema.AllOf {
+		if values, ok := exampleValue(swagger, part, request, seen).(map[string]interface{}); ok {
+			for name, value := range values {
+				obj[name] = value
+			}
+		}
+	}
+	for name, property := range schema.Properties {
+		if request && property.ReadOnly {
+			continue
+		}
+		obj[name] = exampleValue(swagger, property, request, seen)
+	}
+	if schema.AdditionalProperties != nil && len(obj) == 0 {
+		obj["key"] = exampleValue(swagger, *schema.AdditionalProperties, request, seen)
+	}
+	return obj
+}
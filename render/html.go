@@ -0,0 +1,137 @@
+package render
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+const htmlStyle = `body{font-family:-apple-system,Helvetica,Arial,sans-serif;margin:0;display:flex;color:#222}
+nav{width:16em;min-height:100vh;padding:1em;background:#f4f4f4;box-sizing:border-box}
+nav a{display:block;padding:.15em 0;color:#0075a8;text-decoration:none}
+main{flex:1;padding:1em 2em;max-width:60em}
+a{color:#0075a8}
+table{border-collapse:collapse;width:100%;margin-bottom:1em}
+th,td{border:1px solid #ddd;padding:.3em .5em;text-align:left;vertical-align:top}
+th{background:#f4f4f4}
+code,pre{font-family:Menlo,Consolas,monospace;font-size:.9em}
+pre{background:#f4f4f4;padding:1em;overflow:auto}
+.method{display:inline-block;min-width:4em;font-weight:bold}
+.flag{color:#777;font-size:.85em}`
+
+var htmlLayout = `{{define "layout"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+<nav>
+<a href="index.html"><strong>{{.Site.Title}}</strong></a>
+{{range .Site.Pages}}<a href="{{.Name}}.html">{{.Name}}</a>
+{{end}}</nav>
+<main>
+{{template "content" .}}
+</main>
+</body>
+</html>
+{{end}}`
+
+var htmlIndex = template.Must(template.Must(template.New("index").Funcs(htmlFuncs).Parse(htmlLayout)).Parse(`{{define "content"}}
+<h1>{{.Site.Title}}</h1>
+<p>{{.Site.Description}}</p>
+<p>Version: {{.Site.Version}}</p>
+<table>
+<tr><th>Resource</th><th>Description</th></tr>
+{{range .Site.Pages}}<tr><td><a href="{{.Name}}.html">{{.Name}}</a></td><td>{{code .Description}}</td></tr>
+{{end}}</table>
+{{end}}`))
+
+var htmlPage = template.Must(template.Must(template.New("page").Funcs(htmlFuncs).Parse(htmlLayout)).Parse(`{{define "type"}}{{if .Ref}}{{if .Page}}<a href="{{.Ref}}.html">{{.Name}}</a>{{else}}<a href="#{{lower .Ref}}">{{.Name}}</a>{{end}}{{else}}{{.Name}}{{end}}{{end}}
+{{define "fields"}}<table>
+<tr><th>Field</th><th>Type</th><th>Description</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code>{{if .Required}} <span class="flag">required</span>{{end}}{{if .ReadOnly}} <span class="flag">read only</span>{{end}}</td><td>{{template "type" .Type}}</td><td>{{code .Notes}}</td></tr>
+{{end}}</table>
+{{end}}
+{{define "operation"}}<h3><span class="method">{{.Method}}</span> <code>{{.Path}}</code></h3>
+<p>{{code .Description}}</p>
+{{if .Parameters}}<table>
+<tr><th>Parameter</th><th>In</th><th>Description</th></tr>
+{{range .Parameters}}<tr><td><code>{{.Name}}</code>{{if .Required}} <span class="flag">required</span>{{end}}</td><td>{{.In}}</td><td>{{code .Description}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Request}}<p>Request body: {{template "type" .Request}}</p>
+{{end}}{{if .Response}}<p>Response: {{template "type" .Response}}</p>
+{{end}}{{end}}
+{{define "content"}}
+<h1>{{.Page.Name}}</h1>
+<p>{{code .Page.Description}}</p>
+<h2>Fields</h2>
+{{template "fields" .Page.Fields}}
+<h2>Operations</h2>
+{{range .Page.Operations}}{{template "operation" .}}{{end}}
+{{if .Page.Actions}}<h2>Actions</h2>
+{{range .Page.Actions}}{{template "operation" .}}{{end}}{{end}}
+{{if .Page.Filters}}<h2>Filters</h2>
+<table>
+<tr><th>Parameter</th><th>Description</th></tr>
+{{range .Page.Filters}}<tr><td><code>{{.Name}}</code></td><td>{{code .Description}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Page.Example}}<h2>Example</h2>
+<pre>{{.Page.Example}}</pre>
+{{end}}
+{{if .Page.Types}}<h2>Types</h2>
+{{range .Page.Types}}<h3 id="{{lower .Name}}">{{.Name}}</h3>
+{{if .Description}}<p>{{code .Description}}</p>
+{{end}}{{template "fields" .Fields}}{{end}}{{end}}
+{{end}}`))
+
+var htmlFuncs = template.FuncMap{
+	"code":  htmlCode,
+	"lower": strings.ToLower,
+}
+
+// HTML - write an index.html and a page per resource type into dir.
+// Pages only link to each other, so the site can be browsed offline.
+func HTML(swagger *openapi.OpenAPI, dir string) error {
+	return write(dir, ".html", NewSite(swagger), renderHTMLIndex, renderHTMLPage)
+}
+
+func renderHTMLIndex(site *Site) ([]byte, error) {
+	var out bytes.Buffer
+	err := htmlIndex.ExecuteTemplate(&out, "layout", struct {
+		Title string
+		Site  *Site
+	}{site.Title, site})
+	return out.Bytes(), err
+}
+
+func renderHTMLPage(site *Site, page *Page) ([]byte, error) {
+	var out bytes.Buffer
+	err := htmlPage.ExecuteTemplate(&out, "layout", struct {
+		Title string
+		Site  *Site
+		Page  *Page
+	}{page.Name, site, page})
+	return out.Bytes(), err
+}
+
+// htmlCode - escape s, turning the `code` spans used in descriptions into <code>
+func htmlCode(s string) template.HTML {
+	parts := strings.Split(template.HTMLEscapeString(s), "`")
+	var out strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			out.WriteString("<code>" + part + "</code>")
+			continue
+		}
+		if i%2 == 1 {
+			out.WriteString("`")
+		}
+		out.WriteString(part)
+	}
+	return template.HTML(out.String())
+}
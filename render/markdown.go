@@ -0,0 +1,108 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+var markdownFuncs = template.FuncMap{
+	"cell":  markdownCell,
+	"lower": strings.ToLower,
+}
+
+var markdownIndex = template.Must(template.New("index").Funcs(markdownFuncs).Parse(`# {{.Title}}
+
+{{.Description}}
+
+Version: {{.Version}}
+
+| Resource | Description |
+| --- | --- |
+{{range .Pages}}| [{{.Name}}]({{.Name}}.md) | {{cell .Description}} |
+{{end}}`))
+
+var markdownPage = template.Must(template.New("page").Funcs(markdownFuncs).Parse(`{{define "type"}}{{if .Ref}}{{if .Page}}[{{.Name}}]({{.Ref}}.md){{else}}[{{.Name}}](#{{lower .Ref}}){{end}}{{else}}{{.Name}}{{end}}{{end -}}
+{{define "fields" -}}
+| Field | Type | Description |
+| --- | --- | --- |
+{{range .}}| ` + "`{{.Name}}`" + `{{if .Required}} (required){{end}}{{if .ReadOnly}} (read only){{end}} | {{template "type" .Type}} | {{cell .Notes}} |
+{{end}}{{end -}}
+{{define "operation" -}}
+### {{.Method}} ` + "`{{.Path}}`" + `
+
+{{.Description}}
+{{if .Parameters}}
+| Parameter | In | Description |
+| --- | --- | --- |
+{{range .Parameters}}| ` + "`{{.Name}}`" + `{{if .Required}} (required){{end}} | {{.In}} | {{cell .Description}} |
+{{end}}{{end}}{{if .Request}}
+Request body: {{template "type" .Request}}
+{{end}}{{if .Response}}
+Response: {{template "type" .Response}}
+{{end}}
+{{end -}}
+# {{.Page.Name}}
+
+{{.Page.Description}}
+
+[Index](index.md)
+
+## Fields
+
+{{template "fields" .Page.Fields}}
+## Operations
+
+{{range .Page.Operations}}{{template "operation" .}}{{end}}
+{{- if .Page.Actions}}## Actions
+
+{{range .Page.Actions}}{{template "operation" .}}{{end}}{{end}}
+{{- if .Page.Filters}}## Filters
+
+| Parameter | Description |
+| --- | --- |
+{{range .Page.Filters}}| ` + "`{{.Name}}`" + ` | {{cell .Description}} |
+{{end}}
+{{end}}
+{{- if .Page.Example}}## Example
+
+` + "```json" + `
+{{.Page.Example}}
+` + "```" + `
+
+{{end}}
+{{- if .Page.Types}}## Types
+{{range .Page.Types}}
+### {{.Name}}
+
+{{if .Description}}{{.Description}}
+
+{{end}}{{template "fields" .Fields}}{{end}}{{end}}`))
+
+// Markdown - write an index.md and a page per resource type into dir
+func Markdown(swagger *openapi.OpenAPI, dir string) error {
+	return write(dir, ".md", NewSite(swagger), renderMarkdownIndex, renderMarkdownPage)
+}
+
+func renderMarkdownIndex(site *Site) ([]byte, error) {
+	var out bytes.Buffer
+	err := markdownIndex.Execute(&out, site)
+	return out.Bytes(), err
+}
+
+func renderMarkdownPage(site *Site, page *Page) ([]byte, error) {
+	var out bytes.Buffer
+	err := markdownPage.Execute(&out, struct {
+		Site *Site
+		Page *Page
+	}{site, page})
+	return out.Bytes(), err
+}
+
+// markdownCell - keep a value on one line of a table
+func markdownCell(s string) string {
+	s = strings.Replace(s, "|", "\\|", -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
@@ -0,0 +1,464 @@
+// Package render turns a generated OpenAPI document into static reference docs,
+// one page per resource type, readable without swagger-ui.
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+const schemaPrefix = "#/components/schemas/"
+
+// schemaLink - a Markdown link to a component schema in a description,
+// only swagger-ui can follow it
+var schemaLink = regexp.MustCompile(`\[([^\]]+)\]\(#/components/schemas/[^)]+\)`)
+
+// Site - everything rendered from one OpenAPI document
+type Site struct {
+	Title       string
+	Description string
+	Version     string
+	Pages       []*Page
+}
+
+// Page - a resource type, found through the tags on its operations
+type Page struct {
+	Name        string
+	Description string
+	Fields      []Field
+	Operations  []Operation
+	Actions     []Operation
+	Filters     []Parameter
+	Types       []Type
+	Example     string
+}
+
+// Type - a schema used by a resource type that doesn't have a page of its own
+type Type struct {
+	Name        string
+	Description string
+	Fields      []Field
+}
+
+// Field - a schema property
+type Field struct {
+	Name        string
+	Type        TypeRef
+	Description string
+	Required    bool
+	ReadOnly    bool
+	Default     string
+	Enum        []string
+}
+
+// Notes - description, options and default of a field
+func (f Field) Notes() string {
+	notes := make([]string, 0)
+	if f.Description != "" {
+		notes = append(notes, f.Description)
+	}
+	if len(f.Enum) > 0 {
+		notes = append(notes, fmt.Sprintf("Options: %s", strings.Join(f.Enum, ", ")))
+	}
+	if f.Default != "" {
+		notes = append(notes, fmt.Sprintf("Default: `%s`", f.Default))
+	}
+	return strings.Join(notes, "; ")
+}
+
+// Operation - a method on a path
+type Operation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Parameters  []Parameter
+	Request     *TypeRef
+	Response    *TypeRef
+}
+
+// TypeRef - a type and the schema it references, if any.
+// Page is set when the referenced schema has a page of its own, otherwise
+// it is documented in the Types of the page referencing it.
+type TypeRef struct {
+	Name string
+	Ref  string
+	Page bool
+}
+
+// Parameter - a path or query parameter
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+}
+
+// NewSite - collect the pages for every tag used on an operation
+func NewSite(swagger *openapi.OpenAPI) *Site {
+	site := &Site{
+		Title:       swagger.Info.Title,
+		Description: swagger.Info.Description,
+		Version:     swagger.Info.Version,
+	}
+
+	pages := make(map[string]*Page)
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		for _, method := range openapi.Methods {
+			op := pathItem.Operation(method)
+			if op == nil {
+				continue
+			}
+			for _, tag := range op.Tags {
+				page, ok := pages[tag]
+				if !ok {
+					page = &Page{Name: tag}
+					pages[tag] = page
+				}
+				page.add(swagger, path, method, pathItem, op)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(pages))
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		page := pages[name]
+		schema := swagger.Components.Schemas[name]
+		page.Description = schema.Description
+		if page.Description == "" {
+			page.Description = fmt.Sprintf("`%s` resource.", name)
+		}
+		page.Fields = fields(swagger, schema)
+		page.Types = types(swagger, page, pages)
+		page.Example = example(swagger, name)
+		site.Pages = append(site.Pages, page)
+	}
+
+	for _, page := range site.Pages {
+		page.link(pages)
+	}
+
+	return site
+}
+
+func (p *Page) add(swagger *openapi.OpenAPI, path string, method string, pathItem openapi.PathItem, op *openapi.Operation) {
+	o := Operation{
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+	}
+
+	for _, param := range swagger.Parameters(pathItem, op) {
+		// the action is part of the path of each action
+		if len(op.Actions()) > 0 && param.In == "query" && param.Name == "action" {
+			continue
+		}
+		rp := Parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required,
+		}
+		o.Parameters = append(o.Parameters, rp)
+		// the query opening a websocket isn't a filter
+		if param.In == "query" && !op.Selects(param) && !p.hasFilter(param.Name) {
+			p.Filters = append(p.Filters, rp)
+		}
+	}
+
+	if op.RequestBody != nil {
+		o.Request = mediaTypeRef(op.RequestBody.Content)
+	}
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if ref := mediaTypeRef(op.Responses[code].Content); ref != nil {
+			o.Response = ref
+			break
+		}
+	}
+
+	if len(op.Actions()) == 0 {
+		p.Operations = append(p.Operations, o)
+		return
+	}
+
+	names := make([]string, 0, len(op.Actions()))
+	for name := range op.Actions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		action := op.Actions()[name]
+		a := o
+		a.Path = fmt.Sprintf("%s?action=%s", path, name)
+		a.Summary = name
+		a.Description = action.Description
+		a.Request, a.Response = schemaRef(action.Input), schemaRef(action.Output)
+		p.Actions = append(p.Actions, a)
+	}
+
+	// the create body and response are the first of the oneOf with the actions'
+	if !op.ActionRequired() {
+		if op.RequestBody != nil {
+			o.Request = mediaTypeRef(firstOf(op.RequestBody.Content))
+		}
+		o.Response = mediaTypeRef(firstOf(op.Responses["200"].Content))
+		p.Operations = append(p.Operations, o)
+	}
+}
+
+// link - flag the types that have a page of their own
+func (p *Page) link(pages map[string]*Page) {
+	linkFields := func(fields []Field) {
+		for i := range fields {
+			fields[i].Type.Page = pages[fields[i].Type.Ref] != nil
+		}
+	}
+	linkFields(p.Fields)
+	for _, t := range p.Types {
+		linkFields(t.Fields)
+	}
+	for _, ops := range [][]Operation{p.Operations, p.Actions} {
+		for _, op := range ops {
+			for _, ref := range []*TypeRef{op.Request, op.Response} {
+				if ref != nil {
+					ref.Page = pages[ref.Ref] != nil
+				}
+			}
+		}
+	}
+}
+
+func (p *Page) hasFilter(name string) bool {
+	for _, filter := range p.Filters {
+		if filter.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// write - render the index and every page into dir
+func write(dir string, ext string, site *Site, index func(*Site) ([]byte, error), page func(*Site, *Page) ([]byte, error)) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	out, err := index(site)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "index"+ext), out, 0644)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range site.Pages {
+		out, err := page(site, p)
+		if err != nil {
+			return fmt.Errorf("Failed to render %s - %v", p.Name, err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, p.Name+ext), out, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func schemaRef(schema *openapi.Schema) *TypeRef {
+	if schema == nil {
+		return nil
+	}
+	ref := typeRef(*schema)
+	return &ref
+}
+
+// firstOf - content with the first schema of a oneOf
+func firstOf(content map[string]openapi.MediaType) map[string]openapi.MediaType {
+	first := make(map[string]openapi.MediaType, len(content))
+	for name, mediaType := range content {
+		if mediaType.Schema != nil && len(mediaType.Schema.OneOf) > 0 {
+			mediaType.Schema = &mediaType.Schema.OneOf[0]
+		}
+		first[name] = mediaType
+	}
+	return first
+}
+
+func mediaTypeRef(content map[string]openapi.MediaType) *TypeRef {
+	for _, mediaType := range content {
+		if mediaType.Schema != nil {
+			ref := typeRef(*mediaType.Schema)
+			return &ref
+		}
+	}
+	return nil
+}
+
+// fields - the properties of schema, including the ones of the schemas it extends
+func fields(swagger *openapi.OpenAPI, schema openapi.Schema) []Field {
+	properties, required := flatten(swagger, schema, map[string]bool{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]Field, 0, len(names))
+	for _, name := range names {
+		property := properties[name]
+		field := Field{
+			Name:        name,
+			Type:        typeRef(property),
+			Description: schemaLink.ReplaceAllString(property.Description, "`$1`"),
+			Required:    contains(required, name),
+			ReadOnly:    property.ReadOnly,
+		}
+		enum := property.Enum
+		if len(enum) == 0 && property.Items != nil {
+			enum = property.Items.Enum
+		}
+		for _, value := range enum {
+			field.Enum = append(field.Enum, fmt.Sprint(value))
+		}
+		if property.Default != nil {
+			field.Default = fmt.Sprint(property.Default)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// flatten - the properties and required properties of schema merged over
+// the ones of its allOf parts
+func flatten(swagger *openapi.OpenAPI, schema openapi.Schema, seen map[string]bool) (map[string]openapi.Schema, []string) {
+	properties := make(map[string]openapi.Schema)
+	required := make([]string, 0)
+	for _, part := range schema.AllOf {
+		if part.Ref != "" {
+			name := strings.TrimPrefix(part.Ref, schemaPrefix)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			part = swagger.Components.Schemas[name]
+		}
+		partProperties, partRequired := flatten(swagger, part, seen)
+		for name, property := range partProperties {
+			properties[name] = property
+		}
+		required = append(required, partRequired...)
+	}
+	for name, property := range schema.Properties {
+		properties[name] = property
+	}
+	return properties, append(required, schema.Required...)
+}
+
+// types - schemas reachable from a page that aren't pages themselves
+func types(swagger *openapi.OpenAPI, page *Page, pages map[string]*Page) []Type {
+	seen := map[string]bool{page.Name: true}
+	queue := make([]string, 0)
+	visit := func(name string) {
+		if name != "" && !seen[name] && pages[name] == nil {
+			seen[name] = true
+			queue = append(queue, name)
+		}
+	}
+
+	for _, field := range page.Fields {
+		visit(field.Type.Ref)
+	}
+	for _, op := range append(append([]Operation{}, page.Operations...), page.Actions...) {
+		if op.Request != nil {
+			visit(op.Request.Ref)
+		}
+		if op.Response != nil {
+			visit(op.Response.Ref)
+		}
+	}
+
+	types := make([]Type, 0)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		schema, ok := swagger.Components.Schemas[name]
+		if !ok {
+			continue
+		}
+		t := Type{
+			Name:        name,
+			Description: schema.Description,
+			Fields:      fields(swagger, schema),
+		}
+		for _, field := range t.Fields {
+			visit(field.Type.Ref)
+		}
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types
+}
+
+// typeRef - readable type of a schema and the schema it references
+func typeRef(schema openapi.Schema) TypeRef {
+	switch {
+	case schema.Ref != "":
+		name := strings.TrimPrefix(schema.Ref, schemaPrefix)
+		return TypeRef{Name: name, Ref: name}
+	case schema.Type == "array" && schema.Items != nil:
+		ref := typeRef(*schema.Items)
+		ref.Name = fmt.Sprintf("array[%s]", ref.Name)
+		return ref
+	case schema.Type == "object" && schema.AdditionalProperties != nil:
+		ref := typeRef(*schema.AdditionalProperties)
+		ref.Name = fmt.Sprintf("map[%s]", ref.Name)
+		return ref
+	case len(schema.OneOf) > 0:
+		names := make([]string, 0, len(schema.OneOf))
+		for _, s := range schema.OneOf {
+			names = append(names, typeRef(s).Name)
+		}
+		return TypeRef{Name: strings.Join(names, " | ")}
+	case schema.Format != "":
+		return TypeRef{Name: fmt.Sprintf("%s (%s)", schema.Type, schema.Format)}
+	}
+	return TypeRef{Name: schema.Type}
+}
+
+func contains(a []string, x string) bool {
+	for _, n := range a {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
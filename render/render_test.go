@@ -0,0 +1,180 @@
+package render
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func testSwagger() *openapi.OpenAPI {
+	ref := func(name string) map[string]openapi.MediaType {
+		return map[string]openapi.MediaType{
+			"application/json": {Schema: &openapi.Schema{Ref: schemaPrefix + name}},
+		}
+	}
+	return &openapi.OpenAPI{
+		Info: openapi.Info{Title: "Rancher API"},
+		Paths: map[string]openapi.PathItem{
+			"/clusters": {
+				Get: &openapi.Operation{
+					Tags:        []string{"cluster"},
+					Description: "`clusters` Collection",
+					Parameters: []openapi.Parameter{
+						{Name: "name", In: "query", Description: "Filter by `name`"},
+					},
+					Responses: map[string]openapi.Response{"200": {Content: ref("cluster")}},
+				},
+			},
+			"/clusters/{clusterId}": {
+				Parameters: []openapi.Parameter{{Ref: "#/components/parameters/clusterId"}},
+				Get: &openapi.Operation{
+					Tags:       []string{"cluster"},
+					Parameters: []openapi.Parameter{{Name: "shell", In: "query"}},
+					Responses:  map[string]openapi.Response{"200": {Content: ref("cluster")}},
+					Extensions: openapi.Extensions{"x-websocket": &openapi.Websocket{Query: map[string]string{"shell": "true"}}},
+				},
+				Post: &openapi.Operation{
+					Tags: []string{"cluster"},
+					Parameters: []openapi.Parameter{
+						{Name: "action", In: "query", Required: true, Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"generateKubeconfig"}}},
+					},
+					Responses: map[string]openapi.Response{"200": {Content: ref("generateKubeConfigOutput")}},
+					Extensions: openapi.Extensions{"x-rancher-actions": map[string]openapi.Action{
+						"generateKubeconfig": {Output: &openapi.Schema{Ref: "#/components/schemas/generateKubeConfigOutput"}},
+					}},
+				},
+			},
+			"/nodes": {
+				Get: &openapi.Operation{
+					Tags:      []string{"node"},
+					Responses: map[string]openapi.Response{"200": {Content: ref("node")}},
+				},
+			},
+		},
+		Components: openapi.Components{
+			Parameters: map[string]openapi.Parameter{
+				"clusterId": {Name: "clusterId", In: "path", Required: true},
+			},
+			Schemas: map[string]openapi.Schema{
+				"cluster": {
+					Type:     "object",
+					Required: []string{"name"},
+					Properties: map[string]openapi.Schema{
+						"name":       {Type: "string", Description: "Name | of cluster"},
+						"conditions": {Type: "array", Items: &openapi.Schema{Ref: schemaPrefix + "clusterCondition"}},
+					},
+				},
+				"clusterCondition": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"status": {Type: "string", Enum: []interface{}{"True", "False"}},
+					},
+				},
+				"generateKubeConfigOutput": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"config": {Type: "string"},
+					},
+				},
+				"node": {
+					AllOf: []openapi.Schema{
+						{Ref: schemaPrefix + "resource"},
+						{
+							Type: "object",
+							Properties: map[string]openapi.Schema{
+								"cluster":   {Ref: schemaPrefix + "cluster"},
+								"clusterId": {Type: "string", Description: "Id of [cluster](#/components/schemas/cluster)"},
+							},
+						},
+					},
+				},
+				"resource": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"id": {Type: "string", ReadOnly: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNewSite(t *testing.T) {
+	site := NewSite(testSwagger())
+
+	if len(site.Pages) != 2 || site.Pages[0].Name != "cluster" || site.Pages[1].Name != "node" {
+		t.Fatalf("expected cluster and node pages, got %+v", site.Pages)
+	}
+	cluster := site.Pages[0]
+	if len(cluster.Operations) != 2 || len(cluster.Actions) != 1 {
+		t.Errorf("expected 2 operations and 1 action, got %+v %+v", cluster.Operations, cluster.Actions)
+	} else if a := cluster.Actions[0]; a.Path != "/clusters/{clusterId}?action=generateKubeconfig" || a.Summary != "generateKubeconfig" || len(a.Parameters) != 1 {
+		t.Errorf("expected generateKubeconfig with its action in the path, got %+v", a)
+	}
+	if len(cluster.Filters) != 1 || cluster.Filters[0].Name != "name" {
+		t.Errorf("expected name filter, got %+v", cluster.Filters)
+	}
+	if p := cluster.Operations[1].Parameters; len(p) != 2 || p[0].Name != "clusterId" || !p[0].Required {
+		t.Errorf("expected resolved clusterId parameter, got %+v", p)
+	}
+	if len(cluster.Types) != 2 || cluster.Types[0].Name != "clusterCondition" || cluster.Types[1].Name != "generateKubeConfigOutput" {
+		t.Errorf("expected clusterCondition and generateKubeConfigOutput types, got %+v", cluster.Types)
+	}
+	if f := cluster.Fields[0]; f.Name != "conditions" || f.Type.Name != "array[clusterCondition]" || f.Type.Page {
+		t.Errorf("unexpected conditions field %+v", f)
+	}
+	node := site.Pages[1]
+	if f := node.Fields[0]; f.Type.Ref != "cluster" || !f.Type.Page {
+		t.Errorf("expected node.cluster to link to the cluster page, got %+v", f)
+	}
+	if len(node.Fields) != 3 || node.Fields[2].Name != "id" || !node.Fields[2].ReadOnly {
+		t.Errorf("expected node to have the id of the resource it extends, got %+v", node.Fields)
+	}
+	if f := node.Fields[1]; f.Description != "Id of `cluster`" {
+		t.Errorf("expected the link to the cluster schema as code, got %+v", f)
+	}
+	if !strings.Contains(node.Example, `"id": "string"`) {
+		t.Errorf("expected the node example to have the id of the resource, got %s", node.Example)
+	}
+	if !strings.Contains(cluster.Example, `"status": "True"`) {
+		t.Errorf("expected example to use the first option, got %s", cluster.Example)
+	}
+}
+
+func TestMarkdownAndHTML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	swagger := testSwagger()
+	if err := Markdown(swagger, filepath.Join(dir, "md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := HTML(swagger, filepath.Join(dir, "html")); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, expected := range map[string][]string{
+		"md/index.md":       {"[cluster](cluster.md)", "[node](node.md)"},
+		"md/cluster.md":     {"## Actions", "[array[clusterCondition]](#clustercondition)", "Name \\| of cluster", "### clusterCondition"},
+		"md/node.md":        {"[cluster](cluster.md)"},
+		"html/index.html":   {`<a href="cluster.html">cluster</a>`},
+		"html/cluster.html": {`<h3 id="clustercondition">clusterCondition</h3>`, "Filter by <code>name</code>"},
+	} {
+		out, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range expected {
+			if !strings.Contains(string(out), s) {
+				t.Errorf("%s: missing %s", file, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,449 @@
+// Package samples adds copy-and-paste invocations of every operation as
+// x-codeSamples, read by swagger-ui and redoc.
+package samples
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	"github.com/rancher/gen-api-docs/render"
+)
+
+const (
+	// URLVariable - environment variable holding the API root in the samples
+	URLVariable = "RANCHER_URL"
+	// ServerVariable - environment variable holding the server URL, for paths
+	// with their own servers relative to the server root, e.g. /v3-public
+	ServerVariable = "RANCHER_SERVER"
+	// TokenVariable - environment variable holding the bearer token in the samples
+	TokenVariable = "RANCHER_TOKEN"
+	// AccessKeyVariable - environment variable holding the basic auth user in the samples
+	AccessKeyVariable = "RANCHER_ACCESS_KEY"
+	// SecretKeyVariable - environment variable holding the basic auth password in the samples
+	SecretKeyVariable = "RANCHER_SECRET_KEY"
+)
+
+var pathParams = regexp.MustCompile("{(\\w+)}")
+
+// Auth - how a sample authenticates
+type Auth int
+
+const (
+	// NoAuth - anonymous request
+	NoAuth Auth = iota
+	// BearerAuth - Authorization: Bearer $RANCHER_TOKEN
+	BearerAuth
+	// BasicAuth - $RANCHER_ACCESS_KEY:$RANCHER_SECRET_KEY
+	BasicAuth
+)
+
+// Request - what the samples need to know about an operation
+type Request struct {
+	Method string
+	// Server - variable holding the URL Path is relative to, URLVariable when empty
+	Server string
+	// Path relative to Server, path parameters as {name}
+	Path string
+	// Fixed - query parameters with a value, as name=value, e.g. action=login
+	Fixed []string
+	// Query - required query parameters, sent with an empty value
+	Query []string
+	// Headers - required header parameters with a known value, as Name: value
+	Headers []string
+	Auth    Auth
+	// Body - JSON request example, if the operation takes one
+	Body interface{}
+	// Websocket - the operation upgrades the connection to a websocket
+	Websocket bool
+}
+
+// Add - set the code samples on every operation of swagger
+func Add(swagger *openapi.OpenAPI) {
+	for path, pathItem := range swagger.Paths {
+		for method, op := range pathItem.Operations() {
+			r := NewRequest(swagger, path, method, pathItem, op)
+			switch {
+			case len(op.Actions()) > 0:
+				op.SetCodeSamples(actionSamples(swagger, r, op))
+			case op.Websocket() != nil && !op.Upgrades():
+				ws := websocketRequest(swagger, r, pathItem, op)
+				op.SetCodeSamples(append(Generate(r), labelled(Generate(ws), ws.Fixed...)...))
+			default:
+				op.SetCodeSamples(Generate(r))
+			}
+		}
+	}
+}
+
+// websocketRequest - r opening the websocket of a GET that is only upgraded
+// with its query, along with the upgrade headers the GET doesn't require
+func websocketRequest(swagger *openapi.OpenAPI, r Request, pathItem openapi.PathItem, op *openapi.Operation) Request {
+	r.Websocket = true
+	r.Headers = nil
+	query := op.Websocket().Query
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.Fixed = append(r.Fixed, fmt.Sprintf("%s=%s", name, query[name]))
+	}
+	for _, param := range swagger.Parameters(pathItem, op) {
+		if param.In != "header" {
+			continue
+		}
+		if value := headerValue(param); value != "" {
+			r.Headers = append(r.Headers, fmt.Sprintf("%s: %s", param.Name, value))
+		}
+	}
+	return r
+}
+
+// labelled - samples with the query that sets them apart added to their label
+func labelled(samples []openapi.CodeSample, query ...string) []openapi.CodeSample {
+	for i := range samples {
+		samples[i].Label = strings.Join(append([]string{samples[i].Label}, query...), " ")
+	}
+	return samples
+}
+
+// actionSamples - the samples of every action of op, labelled with the action.
+// A POST that also creates without an action keeps its own samples first.
+func actionSamples(swagger *openapi.OpenAPI, r Request, op *openapi.Operation) []openapi.CodeSample {
+	samples := make([]openapi.CodeSample, 0)
+	if !op.ActionRequired() {
+		samples = append(samples, Generate(r)...)
+	}
+
+	names := make([]string, 0, len(op.Actions()))
+	for name := range op.Actions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		a := r
+		a.Fixed = append([]string{"action=" + name}, r.Fixed...)
+		a.Body = nil
+		if input := op.Actions()[name].Input; input != nil {
+			a.Body = normalize(render.Example(swagger, *input, true))
+		}
+		samples = append(samples, labelled(Generate(a), name)...)
+	}
+	return samples
+}
+
+// NewRequest - collect the path, parameters, auth and request example of an operation
+func NewRequest(swagger *openapi.OpenAPI, path string, method string, pathItem openapi.PathItem, op *openapi.Operation) Request {
+	r := Request{
+		Method:    method,
+		Path:      path,
+		Auth:      auth(swagger, op),
+		Websocket: op.Upgrades(),
+	}
+	if len(pathItem.Servers) > 0 {
+		r.Server = ServerVariable
+	}
+
+	for _, param := range swagger.Parameters(pathItem, op) {
+		// set by the samples of each action or the sample opening the websocket
+		if op.Selects(param) {
+			continue
+		}
+		if param.In == "query" && param.Required {
+			r.Query = append(r.Query, param.Name)
+		}
+		if param.In == "header" && param.Required {
+			if value := headerValue(param); value != "" {
+				r.Headers = append(r.Headers, fmt.Sprintf("%s: %s", param.Name, value))
+			}
+		}
+	}
+
+	if op.RequestBody != nil {
+		if mediaType, ok := op.RequestBody.Content["application/json"]; ok && mediaType.Schema != nil {
+			r.Body = normalize(render.Example(swagger, *mediaType.Schema, true))
+		}
+	}
+
+	return r
+}
+
+// Generate - curl, Go and Python samples for a request.
+// Websockets only get curl, which prints what is received.
+func Generate(r Request) []openapi.CodeSample {
+	if r.Websocket {
+		return []openapi.CodeSample{
+			{Lang: "Shell", Label: "curl", Source: Curl(r)},
+		}
+	}
+	return []openapi.CodeSample{
+		{Lang: "Shell", Label: "curl", Source: Curl(r)},
+		{Lang: "Go", Label: "net/http", Source: Go(r)},
+		{Lang: "Python", Label: "requests", Source: Python(r)},
+	}
+}
+
+// Curl - shell sample, path parameters are read from upper case environment variables
+func Curl(r Request) string {
+	lines := []string{fmt.Sprintf("curl -X %s \"%s\"", r.Method, r.url(func(name string) string {
+		return fmt.Sprintf("${%s}", name)
+	}))}
+	if r.Websocket {
+		lines = append(lines, "--http1.1 --no-buffer")
+	}
+
+	switch r.Auth {
+	case BearerAuth:
+		lines = append(lines, fmt.Sprintf("-H \"Authorization: Bearer ${%s}\"", TokenVariable))
+	case BasicAuth:
+		lines = append(lines, fmt.Sprintf("-u \"${%s}:${%s}\"", AccessKeyVariable, SecretKeyVariable))
+	}
+	for _, header := range r.Headers {
+		lines = append(lines, fmt.Sprintf("-H %q", header))
+	}
+	if r.Body != nil {
+		body, _ := json.MarshalIndent(r.Body, "", "  ")
+		lines = append(lines,
+			"-H \"Content-Type: application/json\"",
+			fmt.Sprintf("-d '%s'", strings.Replace(string(body), "'", "'\\''", -1)))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
+
+// Go - net/http sample, a complete main package
+func Go(r Request) string {
+	imports := []string{"fmt", "io/ioutil", "net/http", "os"}
+	body := "nil"
+	if r.Body != nil {
+		imports = append(imports, "strings")
+		out, _ := json.MarshalIndent(r.Body, "\t", "\t")
+		body = fmt.Sprintf("strings.NewReader(`%s`)", strings.Replace(string(out), "`", "` + \"`\" + `", -1))
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("package main\n\nimport (\n")
+	for _, i := range imports {
+		fmt.Fprintf(b, "\t%q\n", i)
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	fmt.Fprintf(b, "\treq, err := http.NewRequest(%q, %s, %s)\n", r.Method, r.concat(func(name string) string {
+		return fmt.Sprintf("os.Getenv(%q)", name)
+	}), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	switch r.Auth {
+	case BearerAuth:
+		fmt.Fprintf(b, "\treq.Header.Set(\"Authorization\", \"Bearer \"+os.Getenv(%q))\n", TokenVariable)
+	case BasicAuth:
+		fmt.Fprintf(b, "\treq.SetBasicAuth(os.Getenv(%q), os.Getenv(%q))\n", AccessKeyVariable, SecretKeyVariable)
+	}
+	for _, header := range r.Headers {
+		parts := strings.SplitN(header, ": ", 2)
+		fmt.Fprintf(b, "\treq.Header.Set(%q, %q)\n", parts[0], parts[1])
+	}
+	if r.Body != nil {
+		b.WriteString("\treq.Header.Set(\"Content-Type\", \"application/json\")\n")
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tout, err := ioutil.ReadAll(resp.Body)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tfmt.Println(resp.Status, string(out))\n}\n")
+
+	out, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return b.String()
+	}
+	return string(out)
+}
+
+// Python - requests sample
+func Python(r Request) string {
+	args := []string{r.concat(func(name string) string {
+		return fmt.Sprintf("os.environ[%q]", name)
+	})}
+	headers := make([]string, 0)
+	switch r.Auth {
+	case BearerAuth:
+		headers = append(headers, fmt.Sprintf("\"Authorization\": \"Bearer \" + os.environ[%q]", TokenVariable))
+	case BasicAuth:
+		args = append(args, fmt.Sprintf("auth=(os.environ[%q], os.environ[%q])", AccessKeyVariable, SecretKeyVariable))
+	}
+	for _, header := range r.Headers {
+		parts := strings.SplitN(header, ": ", 2)
+		headers = append(headers, fmt.Sprintf("%q: %q", parts[0], parts[1]))
+	}
+	if len(headers) > 0 {
+		args = append(args, "headers={"+strings.Join(headers, ", ")+"}")
+	}
+	if r.Body != nil {
+		args = append(args, "json="+python(r.Body, "    "))
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("import os\n\nimport requests\n\n")
+	fmt.Fprintf(b, "response = requests.request(\n    %q,\n", r.Method)
+	for _, arg := range args {
+		fmt.Fprintf(b, "    %s,\n", arg)
+	}
+	b.WriteString(")\nprint(response.status_code, response.text)\n")
+	return b.String()
+}
+
+// url - the request URL with variables in the syntax of var
+func (r Request) url(variable func(string) string) string {
+	path := pathParams.ReplaceAllStringFunc(r.Path, func(m string) string {
+		return variable(envName(m[1 : len(m)-1]))
+	})
+	return variable(r.server()) + path + r.query()
+}
+
+// concat - the request URL as a concatenation of string literals and variables
+func (r Request) concat(variable func(string) string) string {
+	parts := []string{variable(r.server())}
+	path := r.Path + r.query()
+	last := 0
+	for _, loc := range pathParams.FindAllStringSubmatchIndex(path, -1) {
+		if loc[0] > last {
+			parts = append(parts, strconv.Quote(path[last:loc[0]]))
+		}
+		parts = append(parts, variable(envName(path[loc[2]:loc[3]])))
+		last = loc[1]
+	}
+	if last < len(path) {
+		parts = append(parts, strconv.Quote(path[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func (r Request) server() string {
+	if r.Server == "" {
+		return URLVariable
+	}
+	return r.Server
+}
+
+func (r Request) query() string {
+	params := append([]string{}, r.Fixed...)
+	for _, name := range r.Query {
+		params = append(params, name+"=")
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "?" + strings.Join(params, "&")
+}
+
+// headerValue - the only value a header can have, or its example
+func headerValue(param openapi.Parameter) string {
+	if param.Schema == nil {
+		return ""
+	}
+	if len(param.Schema.Enum) > 0 {
+		return fmt.Sprint(param.Schema.Enum[0])
+	}
+	if example, ok := param.Schema.Example.(string); ok {
+		return example
+	}
+	return ""
+}
+
+// envName - clusterId -> CLUSTER_ID
+func envName(name string) string {
+	b := &strings.Builder{}
+	for i, c := range name {
+		if unicode.IsUpper(c) && i > 0 {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
+}
+
+// auth - the scheme a sample uses, bearer when the operation accepts it
+func auth(swagger *openapi.OpenAPI, op *openapi.Operation) Auth {
+	requirements := swagger.Security
+	if op.Security != nil {
+		requirements = *op.Security
+	}
+
+	found := NoAuth
+	for _, requirement := range requirements {
+		for name := range requirement {
+			scheme := swagger.Components.SecuritySchemes[name]
+			if scheme.Type != "http" {
+				continue
+			}
+			switch strings.ToLower(scheme.Scheme) {
+			case "bearer":
+				return BearerAuth
+			case "basic":
+				found = BasicAuth
+			}
+		}
+	}
+	return found
+}
+
+// normalize - round trip through JSON so python only sees JSON types
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// python - a normalized JSON value as a python literal
+func python(v interface{}, indent string) string {
+	switch value := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if value {
+			return "True"
+		}
+		return "False"
+	case string:
+		return strconv.Quote(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []interface{}:
+		if len(value) == 0 {
+			return "[]"
+		}
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, indent+"    "+python(item, indent+"    ")+",")
+		}
+		return "[\n" + strings.Join(items, "\n") + "\n" + indent + "]"
+	case map[string]interface{}:
+		if len(value) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(keys))
+		for _, key := range keys {
+			items = append(items, fmt.Sprintf("%s    %q: %s,", indent, key, python(value[key], indent+"    ")))
+		}
+		return "{\n" + strings.Join(items, "\n") + "\n" + indent + "}"
+	}
+	return fmt.Sprint(v)
+}
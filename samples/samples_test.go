@@ -0,0 +1,201 @@
+package samples
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func testSwagger() *openapi.OpenAPI {
+	return &openapi.OpenAPI{
+		Security: []map[string][]string{{"basic": {}}, {"bearer": {}}},
+		Paths: map[string]openapi.PathItem{
+			"/clusters/{clusterId}": {
+				Parameters: []openapi.Parameter{{Ref: "#/components/parameters/clusterId"}},
+				Post: &openapi.Operation{
+					Parameters: []openapi.Parameter{{Name: "action", In: "query", Required: true}},
+					RequestBody: &openapi.RequestBody{
+						Content: map[string]openapi.MediaType{
+							"application/json": {Schema: &openapi.Schema{Ref: "#/components/schemas/rotateCertificateInput"}},
+						},
+					},
+					Extensions: openapi.Extensions{"x-rancher-actions": map[string]openapi.Action{
+						"generateKubeconfig": {},
+						"rotateCertificates": {Input: &openapi.Schema{Ref: "#/components/schemas/rotateCertificateInput"}},
+					}},
+				},
+			},
+		},
+		Components: openapi.Components{
+			Parameters: map[string]openapi.Parameter{
+				"clusterId": {Name: "clusterId", In: "path", Required: true},
+			},
+			Schemas: map[string]openapi.Schema{
+				"rotateCertificateInput": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"caCertificates": {Type: "boolean"},
+						"services":       {Type: "string", Example: "it's"},
+					},
+				},
+			},
+			SecuritySchemes: map[string]openapi.SecurityScheme{
+				"basic":  {Type: "http", Scheme: "basic"},
+				"bearer": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+}
+
+func TestAdd(t *testing.T) {
+	swagger := testSwagger()
+	Add(swagger)
+
+	op := swagger.Paths["/clusters/{clusterId}"].Post
+	if len(op.CodeSamples()) != 6 {
+		t.Fatalf("expected curl, Go and Python samples for each action, got %+v", op.CodeSamples())
+	}
+	if op.CodeSamples()[0].Label != "curl generateKubeconfig" || op.CodeSamples()[3].Label != "curl rotateCertificates" {
+		t.Errorf("expected samples labelled with their action, got %+v", op.CodeSamples())
+	}
+	if kubeconfig := op.CodeSamples()[0].Source; strings.Contains(kubeconfig, "-d ") {
+		t.Errorf("expected no body for an action without input:\n%s", kubeconfig)
+	}
+	curl, golang, python := op.CodeSamples()[3].Source, op.CodeSamples()[4].Source, op.CodeSamples()[5].Source
+
+	for _, expected := range []string{
+		`curl -X POST "${RANCHER_URL}/clusters/${CLUSTER_ID}?action=rotateCertificates"`,
+		`-H "Authorization: Bearer ${RANCHER_TOKEN}"`,
+		`"services": "it'\''s"`,
+	} {
+		if !strings.Contains(curl, expected) {
+			t.Errorf("expected %s in curl sample:\n%s", expected, curl)
+		}
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", golang, 0); err != nil {
+		t.Errorf("Go sample does not parse - %v:\n%s", err, golang)
+	}
+	if !strings.Contains(golang, `http.NewRequest("POST", os.Getenv("RANCHER_URL")+"/clusters/"+os.Getenv("CLUSTER_ID")+"?action=rotateCertificates", strings.NewReader(`) {
+		t.Errorf("unexpected Go sample:\n%s", golang)
+	}
+
+	for _, expected := range []string{
+		`os.environ["RANCHER_URL"] + "/clusters/" + os.environ["CLUSTER_ID"] + "?action=rotateCertificates"`,
+		`"caCertificates": False,`,
+	} {
+		if !strings.Contains(python, expected) {
+			t.Errorf("expected %s in python sample:\n%s", expected, python)
+		}
+	}
+}
+
+func TestAddUnauthenticated(t *testing.T) {
+	swagger := testSwagger()
+	swagger.Paths["/v3-public/localProviders/local"] = openapi.PathItem{
+		Servers: []openapi.Server{{URL: "https://{hostname}"}},
+		Post: &openapi.Operation{
+			Parameters: []openapi.Parameter{{Name: "action", In: "query", Required: true}},
+			Security:   &[]map[string][]string{},
+			Extensions: openapi.Extensions{"x-rancher-actions": map[string]openapi.Action{"login": {}}},
+		},
+	}
+	Add(swagger)
+
+	curl := swagger.Paths["/v3-public/localProviders/local"].Post.CodeSamples()[0].Source
+	if curl != `curl -X POST "${RANCHER_SERVER}/v3-public/localProviders/local?action=login"` {
+		t.Errorf("expected an unauthenticated request relative to the server, got:\n%s", curl)
+	}
+}
+
+func TestAddWebsocket(t *testing.T) {
+	swagger := testSwagger()
+	swagger.Paths["/subscribe"] = openapi.PathItem{
+		Get: &openapi.Operation{
+			Parameters: []openapi.Parameter{
+				{Name: "Upgrade", In: "header", Required: true, Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"websocket"}}},
+				{Name: "Sec-WebSocket-Key", In: "header", Required: true, Schema: &openapi.Schema{Type: "string", Example: "dGhlIHNhbXBsZSBub25jZQ=="}},
+				{Name: "eventNames", In: "query", Schema: &openapi.Schema{Type: "array"}},
+			},
+			Extensions: openapi.Extensions{"x-websocket": &openapi.Websocket{}},
+		},
+	}
+	Add(swagger)
+
+	samples := swagger.Paths["/subscribe"].Get.CodeSamples()
+	if len(samples) != 1 {
+		t.Fatalf("expected only a curl sample, got %+v", samples)
+	}
+	expected := `curl -X GET "${RANCHER_URL}/subscribe" \
+  --http1.1 --no-buffer \
+  -H "Authorization: Bearer ${RANCHER_TOKEN}" \
+  -H "Upgrade: websocket" \
+  -H "Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ=="`
+	if samples[0].Source != expected {
+		t.Errorf("expected the handshake headers, got:\n%s", samples[0].Source)
+	}
+}
+
+func TestAddShell(t *testing.T) {
+	swagger := testSwagger()
+	cluster := swagger.Paths["/clusters/{clusterId}"]
+	cluster.Get = &openapi.Operation{
+		Parameters: []openapi.Parameter{
+			{Name: "shell", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"true"}}},
+			{Name: "Upgrade", In: "header", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"websocket"}}},
+		},
+		Extensions: openapi.Extensions{"x-websocket": &openapi.Websocket{Query: map[string]string{"shell": "true"}}},
+	}
+	swagger.Paths["/clusters/{clusterId}"] = cluster
+	Add(swagger)
+
+	samples := cluster.Get.CodeSamples()
+	if len(samples) != 4 || samples[3].Label != "curl shell=true" {
+		t.Fatalf("expected the GET samples and a curl sample of the shell, got %+v", samples)
+	}
+	if get := samples[0].Source; strings.Contains(get, "shell") || strings.Contains(get, "Upgrade") {
+		t.Errorf("expected a plain GET of the cluster, got:\n%s", get)
+	}
+	expected := `curl -X GET "${RANCHER_URL}/clusters/${CLUSTER_ID}?shell=true" \
+  --http1.1 --no-buffer \
+  -H "Authorization: Bearer ${RANCHER_TOKEN}" \
+  -H "Upgrade: websocket"`
+	if samples[3].Source != expected {
+		t.Errorf("expected the shell handshake, got:\n%s", samples[3].Source)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	swagger := testSwagger()
+	op := &openapi.Operation{}
+	if a := auth(swagger, op); a != BearerAuth {
+		t.Errorf("expected bearer auth, got %v", a)
+	}
+	op.Security = &[]map[string][]string{{"basic": {}}}
+	if a := auth(swagger, op); a != BasicAuth {
+		t.Errorf("expected basic auth, got %v", a)
+	}
+	op.Security = &[]map[string][]string{}
+	if a := auth(swagger, op); a != NoAuth {
+		t.Errorf("expected no auth for an unauthenticated operation, got %v", a)
+	}
+	swagger.Security = nil
+	if a := auth(swagger, &openapi.Operation{}); a != NoAuth {
+		t.Errorf("expected no auth, got %v", a)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	for in, expected := range map[string]string{
+		"clusterId":            "CLUSTER_ID",
+		"id":                   "ID",
+		"projectRoleBindingId": "PROJECT_ROLE_BINDING_ID",
+	} {
+		if out := envName(in); out != expected {
+			t.Errorf("envName(%s): expected %s, got %s", in, expected, out)
+		}
+	}
+}
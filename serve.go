@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/rancher/gen-api-docs/generator"
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	"github.com/rancher/gen-api-docs/swaggerui"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultListen = ":8080"
+
+// server - hosts the generated spec with swagger-ui and regenerates it on request.
+// Browsers on the UI are told to reload through server sent events.
+type server struct {
+	regenerate func() (*openapi.OpenAPI, *generator.Report, error)
+	// ui - the swagger-ui assets
+	ui http.Handler
+
+	// building is held for a whole regeneration so requests don't crawl twice at once
+	building sync.Mutex
+
+	lock      sync.Mutex
+	json      []byte
+	yaml      []byte
+	report    *generator.Report
+	generated time.Time
+	err       error
+	listeners map[chan time.Time]bool
+}
+
+// serve - generate the docs with regenerate and serve them on listen,
+// with the swagger-ui compiled into the binary
+func serve(regenerate func() (*openapi.OpenAPI, *generator.Report, error), listen string) error {
+	ui, err := swaggerui.Handler()
+	if err != nil {
+		return err
+	}
+	s := newServer(regenerate, ui)
+	if err := s.build(); err != nil {
+		log.Errorf("Failed to generate docs, POST /regenerate to retry - %v", err)
+	}
+	log.Infof("Serving docs on %s", listen)
+	return http.ListenAndServe(listen, s.handler())
+}
+
+func newServer(regenerate func() (*openapi.OpenAPI, *generator.Report, error), ui http.Handler) *server {
+	return &server{
+		regenerate: regenerate,
+		ui:         ui,
+		listeners:  make(map[chan time.Time]bool),
+	}
+}
+
+// specFile - read the docs from a generated swagger.json instead of crawling,
+// for an image serving the docs it is built with
+func specFile(file string) func() (*openapi.OpenAPI, *generator.Report, error) {
+	return func() (*openapi.OpenAPI, *generator.Report, error) {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		swagger := &openapi.OpenAPI{}
+		if err := json.Unmarshal(data, swagger); err != nil {
+			return nil, nil, fmt.Errorf("Failed to read %s - %v", file, err)
+		}
+		return swagger, &generator.Report{}, nil
+	}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.serveIndex)
+	mux.Handle("/swagger-ui/", http.StripPrefix("/swagger-ui/", s.ui))
+	mux.HandleFunc("/swagger.json", s.serveSpec("application/json", func() []byte { return s.json }))
+	mux.HandleFunc("/swagger.yaml", s.serveSpec("application/x-yaml", func() []byte { return s.yaml }))
+	mux.HandleFunc("/report.json", s.serveReport)
+	mux.HandleFunc("/regenerate", s.serveRegenerate)
+	mux.HandleFunc("/events", s.serveEvents)
+	return mux
+}
+
+// build - regenerate the spec and tell the browsers to reload
+func (s *server) build() error {
+	s.building.Lock()
+	defer s.building.Unlock()
+
+	swagger, report, err := s.regenerate()
+	if err == nil {
+		var jsonSpec, yamlSpec []byte
+		jsonSpec, err = json.Marshal(swagger)
+		if err == nil {
+			yamlSpec, err = yaml.Marshal(swagger)
+		}
+		if err == nil {
+			s.lock.Lock()
+			s.json, s.yaml, s.report = jsonSpec, yamlSpec, report
+			s.lock.Unlock()
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err
+	if err != nil {
+		return err
+	}
+	s.generated = time.Now()
+	for listener := range s.listeners {
+		select {
+		case listener <- s.generated:
+		default:
+			// Already has a reload pending
+		}
+	}
+	return nil
+}
+
+func (s *server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, indexHTML)
+}
+
+func (s *server) serveSpec(contentType string, spec func() []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		out, err := spec(), s.err
+		s.lock.Unlock()
+
+		if out == nil {
+			msg := "Docs have not been generated yet"
+			if err != nil {
+				msg = fmt.Sprintf("Failed to generate docs - %v", err)
+			}
+			http.Error(w, msg, http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(out)
+	}
+}
+
+func (s *server) serveReport(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	report := s.report
+	s.lock.Unlock()
+
+	if report == nil {
+		http.Error(w, "Docs have not been generated yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func (s *server) serveRegenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Use POST to regenerate", http.StatusMethodNotAllowed)
+		return
+	}
+
+	log.Info("Regenerate docs")
+	if err := s.build(); err != nil {
+		log.Errorf("Failed to regenerate docs - %v", err)
+		http.Error(w, fmt.Sprintf("Failed to regenerate docs - %v", err), http.StatusBadGateway)
+		return
+	}
+	s.serveReport(w, r)
+}
+
+// serveEvents - server sent events, a reload event each time the spec is regenerated
+func (s *server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	listener := make(chan time.Time, 1)
+	s.lock.Lock()
+	s.listeners[listener] = true
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		delete(s.listeners, listener)
+		s.lock.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case generated := <-listener:
+			fmt.Fprintf(w, "event: reload\ndata: %s\n\n", generated.Format(time.RFC3339))
+			flusher.Flush()
+		}
+	}
+}
+
+// indexHTML - the swagger-ui in the binary, reloading when the spec is regenerated
+const indexHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Rancher API</title>
+  <link rel="stylesheet" href="swagger-ui/swagger-ui.css">
+  <style>
+    body { margin: 0; }
+    #toolbar { padding: 8px 16px; background: #1b1b1b; color: #fff; font-family: sans-serif; font-size: 14px; }
+    #toolbar button { margin-right: 12px; }
+  </style>
+</head>
+<body>
+  <div id="toolbar">
+    <button id="regenerate">Regenerate</button>
+    <span id="status"></span>
+  </div>
+  <div id="swagger-ui"></div>
+  <script src="swagger-ui/swagger-ui-bundle.js"></script>
+  <script src="swagger-ui/swagger-ui-standalone-preset.js"></script>
+  <script>
+    var message = document.getElementById("status");
+    var ui = SwaggerUIBundle({
+      url: "swagger.json",
+      dom_id: "#swagger-ui",
+      deepLinking: true,
+      presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+      layout: "StandaloneLayout"
+    });
+
+    document.getElementById("regenerate").onclick = function () {
+      message.textContent = "Regenerating...";
+      fetch("regenerate", { method: "POST" }).then(function (resp) {
+        if (!resp.ok) {
+          return resp.text().then(function (msg) { message.textContent = msg; });
+        }
+      });
+    };
+
+    new EventSource("events").addEventListener("reload", function (e) {
+      message.textContent = "Generated " + e.data;
+      ui.specActions.download("swagger.json");
+    });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/rancher/gen-api-docs/generator"
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+func TestServe(t *testing.T) {
+	rancher := newFakeRancher(t)
+	defer rancher.Close()
+
+	g := newGenerator(t, rancher)
+	builds := 0
+	s := newServer(func() (*openapi.OpenAPI, *generator.Report, error) {
+		builds++
+		return g.Generate()
+	}, http.NotFoundHandler())
+	srv := httptest.NewServer(s.handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before the first build, got %s", resp.Status)
+	}
+
+	if err := s.build(); err != nil {
+		t.Fatal(err)
+	}
+
+	body := get(t, srv.URL+"/swagger.yaml")
+	swagger := &openapi.OpenAPI{}
+	if err := yaml.Unmarshal([]byte(body), swagger); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := swagger.Paths["/clusters"]; !ok {
+		t.Errorf("expected /clusters in swagger.yaml")
+	}
+	if !strings.Contains(get(t, srv.URL+"/swagger.json"), `"/clusters/{clusterId}"`) {
+		t.Errorf("expected /clusters/{clusterId} in swagger.json")
+	}
+	if !strings.Contains(get(t, srv.URL+"/"), "swagger-ui-bundle.js") {
+		t.Errorf("expected the swagger-ui page")
+	}
+
+	events, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer events.Body.Close()
+	stream := bufio.NewReader(events.Body)
+	if line, _ := stream.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("expected the event stream to open, got %q", line)
+	}
+
+	resp, err = http.Get(srv.URL + "/regenerate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET /regenerate to be rejected, got %s", resp.Status)
+	}
+
+	resp, err = http.Post(srv.URL+"/regenerate", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(report), `"collections"`) {
+		t.Errorf("expected the crawl report from /regenerate, got %s %s", resp.Status, report)
+	}
+	if builds != 2 {
+		t.Errorf("expected 2 builds, got %d", builds)
+	}
+
+	stream.ReadString('\n')
+	if line, _ := stream.ReadString('\n'); line != "event: reload\n" {
+		t.Errorf("expected a reload event, got %q", line)
+	}
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: %s %s", url, resp.Status, body)
+	}
+	return string(body)
+}
+
+func TestServeSwaggerUI(t *testing.T) {
+	ui := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "swagger-ui-bundle.js" {
+			w.Write([]byte("var SwaggerUIBundle;"))
+			return
+		}
+		http.NotFound(w, r)
+	})
+	s := newServer(nil, ui)
+	srv := httptest.NewServer(s.handler())
+	defer srv.Close()
+
+	index := get(t, srv.URL+"/")
+	if strings.Contains(index, "https://") || !strings.Contains(index, `src="swagger-ui/swagger-ui-bundle.js"`) {
+		t.Errorf("expected swagger-ui to be loaded from the server, got:\n%s", index)
+	}
+	if body := get(t, srv.URL+"/swagger-ui/swagger-ui-bundle.js"); body != "var SwaggerUIBundle;" {
+		t.Errorf("expected the bundle in the binary, got %q", body)
+	}
+}
+
+func TestServeSpecFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen-api-docs-spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "swagger.json")
+	if err := ioutil.WriteFile(file, []byte(`{"openapi": "3.0.1", "paths": {"/clusters": {}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newServer(specFile(file), http.NotFoundHandler())
+	if err := s.build(); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.handler())
+	defer srv.Close()
+
+	if body := get(t, srv.URL+"/swagger.yaml"); !strings.Contains(body, "/clusters") {
+		t.Errorf("expected the spec read from the file, got:\n%s", body)
+	}
+
+	s = newServer(specFile(filepath.Join(dir, "missing.json")), http.NotFoundHandler())
+	if err := s.build(); err == nil {
+		t.Errorf("expected an error reading a missing spec")
+	}
+}
@@ -0,0 +1,176 @@
+// Package steve documents the Kubernetes style Steve API Rancher serves at /v1
+// as its own OpenAPI v3 document.
+// Unlike norman every type is listed in the schemas collection with a link to
+// its collection, so the schemas are all that is crawled.
+package steve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/rancher/gen-api-docs/generator"
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultBase - OpenAPI document the Steve paths and schemas are added to
+const DefaultBase = "./data/steve.yml"
+
+// Getter - reads the JSON document at a link, generator.HTTPSource and
+// generator.DirSource both are
+type Getter interface {
+	Get(link string) ([]byte, error)
+}
+
+// Options - what to crawl and where to write the docs
+type Options struct {
+	// URL - Steve API root, e.g. https://rancher.example.com/v1
+	URL string
+	// Source - where the API is read from, defaults to a generator.HTTPSource using Token and Client
+	Source Getter
+	// Token - bearer token for the API requests
+	Token string
+	// Client - HTTP client for the API requests, defaults to one skipping TLS verification
+	Client *http.Client
+
+	// Base - OpenAPI document the paths and schemas are added to, defaults to DefaultBase
+	Base string
+
+	// Output - directory swagger.json and the Exports are written to.
+	// Nothing is written when empty.
+	Output string
+	// Exports - extra formats written to Output, see generator.Export
+	Exports []string
+}
+
+// Schema - a Steve schema, the norman fields plus the Kubernetes attributes
+type Schema struct {
+	ID                string                  `json:"id"`
+	Description       string                  `json:"description,omitempty"`
+	PluralName        string                  `json:"pluralName,omitempty"`
+	Links             map[string]string       `json:"links,omitempty"`
+	ResourceMethods   []string                `json:"resourceMethods,omitempty"`
+	CollectionMethods []string                `json:"collectionMethods,omitempty"`
+	ResourceFields    map[string]norman.Field `json:"resourceFields,omitempty"`
+	Attributes        Attributes              `json:"attributes"`
+}
+
+// Attributes - the Kubernetes resource a schema is served from
+type Attributes struct {
+	Group      string   `json:"group,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Resource   string   `json:"resource,omitempty"`
+	Namespaced bool     `json:"namespaced,omitempty"`
+	Verbs      []string `json:"verbs,omitempty"`
+}
+
+// schemaList - a page of the schemas collection
+type schemaList struct {
+	Data       []Schema           `json:"data"`
+	Pagination *norman.Pagination `json:"pagination,omitempty"`
+}
+
+// Generator - crawls the Steve API described by its Options
+type Generator struct {
+	opts   Options
+	source Getter
+
+	swagger *openapi.OpenAPI
+	schemas map[string]Schema
+}
+
+// New - a Generator for opts
+func New(opts Options) *Generator {
+	g := &Generator{
+		opts:   opts,
+		source: opts.Source,
+	}
+	if g.opts.Base == "" {
+		g.opts.Base = DefaultBase
+	}
+	if g.source == nil {
+		g.source = generator.NewHTTPSource(opts.Token, opts.Client)
+	}
+	return g
+}
+
+// Generate - read the schemas and translate them onto the base document
+func (g *Generator) Generate() (*openapi.OpenAPI, error) {
+	log.Debug("Import base")
+	g.swagger = &openapi.OpenAPI{}
+	yamlFile, err := ioutil.ReadFile(g.opts.Base)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(yamlFile, g.swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	g.swagger.Paths = make(map[string]openapi.PathItem)
+	g.swagger.Components.Parameters = make(map[string]openapi.Parameter)
+	if g.swagger.Components.Schemas == nil {
+		g.swagger.Components.Schemas = make(map[string]openapi.Schema)
+	}
+
+	schemas, err := g.getSchemas()
+	if err != nil {
+		return nil, err
+	}
+	g.schemas = make(map[string]Schema, len(schemas))
+	for _, schema := range schemas {
+		g.schemas[schema.ID] = schema
+	}
+
+	for _, schema := range schemas {
+		if _, ok := schema.Links["collection"]; !ok {
+			log.Debugf("%s has no collection, only documented when referenced", schema.ID)
+			continue
+		}
+		g.translateType(schema)
+	}
+
+	if g.opts.Output != "" {
+		err = generator.Write(g.swagger, nil, g.opts.Output, g.opts.Exports)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return g.swagger, nil
+}
+
+// getSchemas - the schemas collection, following the next page links
+func (g *Generator) getSchemas() ([]Schema, error) {
+	schemas := make([]Schema, 0)
+	seen := make(map[string]bool)
+	for next := g.opts.URL + "/schemas"; next != ""; {
+		if seen[next] {
+			return nil, fmt.Errorf("Pagination of %s/schemas loops back to %s", g.opts.URL, next)
+		}
+		seen[next] = true
+
+		response, err := g.source.Get(next)
+		if err != nil {
+			return nil, err
+		}
+		list := &schemaList{}
+		err = json.Unmarshal(response, list)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s - %v", next, err)
+		}
+		schemas = append(schemas, list.Data...)
+
+		next = ""
+		if list.Pagination != nil {
+			next = list.Pagination.Next
+		}
+	}
+	log.Debugf("Loaded %d schemas from %s/schemas", len(schemas), g.opts.URL)
+	return schemas, nil
+}
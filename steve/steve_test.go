@@ -0,0 +1,200 @@
+package steve
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rancher/gen-api-docs/generator"
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+)
+
+const fixtureURL = "https://rancher.example.com/v1"
+
+func generateFixture(t *testing.T) *openapi.OpenAPI {
+	t.Helper()
+	swagger, err := New(Options{
+		URL:    fixtureURL,
+		Source: generator.NewDirSource("../testdata/steve"),
+		Base:   "../data/steve.yml",
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return swagger
+}
+
+func TestGenerate(t *testing.T) {
+	swagger := generateFixture(t)
+
+	for _, path := range []string{
+		"/apps.deployments",
+		"/apps.deployments/{namespace}",
+		"/apps.deployments/{namespace}/{name}",
+		"/namespaces",
+		"/namespaces/{name}",
+	} {
+		if _, ok := swagger.Paths[path]; !ok {
+			t.Errorf("missing path %s", path)
+		}
+	}
+	for _, path := range []string{
+		"/namespaces/{namespace}",
+		"/namespaces/{namespace}/{name}",
+		"/io.k8s.api.apps.v1.DeploymentSpec",
+	} {
+		if _, ok := swagger.Paths[path]; ok {
+			t.Errorf("unexpected path %s", path)
+		}
+	}
+
+	deployments := swagger.Paths["/apps.deployments"]
+	if deployments.Get == nil || deployments.Post == nil || deployments.Get.Tags[0] != "apps.deployment" {
+		t.Errorf("expected GET and POST on /apps.deployments tagged apps.deployment, got %+v", deployments)
+	}
+	if ref := deployments.Get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/apps.deployments" {
+		t.Errorf("expected the list to return apps.deployments, got %s", ref)
+	}
+	params := make([]string, 0)
+	for _, p := range deployments.Get.Parameters {
+		params = append(params, p.Name)
+	}
+	if strings.Join(params, ",") != "limit,continue,labelSelector,fieldSelector" {
+		t.Errorf("unexpected list parameters %v", params)
+	}
+
+	deployment := swagger.Paths["/apps.deployments/{namespace}/{name}"]
+	if deployment.Get == nil || deployment.Put == nil || deployment.Patch == nil || deployment.Delete == nil {
+		t.Errorf("expected GET, PUT, PATCH and DELETE on a deployment, got %+v", deployment)
+	}
+	if len(deployment.Parameters) != 2 || deployment.Parameters[0].Ref != "#/components/parameters/namespace" || deployment.Parameters[1].Ref != "#/components/parameters/name" {
+		t.Errorf("expected namespace and name parameters, got %+v", deployment.Parameters)
+	}
+	if _, ok := deployment.Patch.RequestBody.Content["application/merge-patch+json"]; !ok {
+		t.Errorf("expected a merge patch body, got %+v", deployment.Patch.RequestBody)
+	}
+
+	// No methods on the namespace schema, they come from the verbs
+	namespaces := swagger.Paths["/namespaces"]
+	namespace := swagger.Paths["/namespaces/{name}"]
+	if namespaces.Get == nil || namespaces.Post == nil || namespace.Get == nil || namespace.Put == nil || namespace.Patch == nil || namespace.Delete == nil {
+		t.Errorf("expected the methods of the namespace verbs, got %+v %+v", namespaces, namespace)
+	}
+
+	schema := swagger.Components.Schemas["apps.deployment"]
+	if len(schema.GroupVersionKind()) != 1 || schema.GroupVersionKind()[0] != (openapi.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}) {
+		t.Errorf("expected the deployment kind, got %+v", schema.GroupVersionKind())
+	}
+	if !strings.Contains(schema.Description, "Namespaced `apps/v1` `Deployment`") {
+		t.Errorf("expected the kind in the description, got %q", schema.Description)
+	}
+	if metadata := schema.Properties["metadata"]; metadata.Ref != "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta" || metadata.Description != "Standard object metadata." {
+		t.Errorf("expected metadata to reference ObjectMeta with its description, got %+v", metadata)
+	}
+	if !strings.Contains(swagger.Components.Schemas["namespace"].Description, "Cluster scoped `v1` `Namespace`") {
+		t.Errorf("expected a cluster scoped core namespace, got %q", swagger.Components.Schemas["namespace"].Description)
+	}
+
+	meta := swagger.Components.Schemas["io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"]
+	if items := meta.Properties["ownerReferences"].Items; items == nil || items.Ref != "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.OwnerReference" {
+		t.Errorf("expected ownerReferences to be an array of OwnerReference, got %+v", meta.Properties["ownerReferences"])
+	}
+	if labels := meta.Properties["labels"]; labels.Type != "object" || labels.AdditionalProperties == nil || labels.AdditionalProperties.Type != "string" {
+		t.Errorf("expected labels to be a map of strings, got %+v", labels)
+	}
+	if created := meta.Properties["creationTimestamp"]; created.Format != "date-time" || !created.ReadOnly {
+		t.Errorf("expected a read only date-time, got %+v", created)
+	}
+	if len(meta.Required) != 1 || meta.Required[0] != "name" {
+		t.Errorf("expected ObjectMeta to require name, got %v", meta.Required)
+	}
+
+	spec := swagger.Components.Schemas["io.k8s.api.apps.v1.DeploymentSpec"]
+	if replicas := spec.Properties["replicas"]; replicas.Type != "integer" || replicas.Minimum == nil || *replicas.Minimum != 0 || replicas.Description != "Number of desired pods." {
+		t.Errorf("unexpected replicas %+v", replicas)
+	}
+	if status := swagger.Components.Schemas["namespace"].Properties["status"]; status.Type != "object" || status.Ref != "" {
+		t.Errorf("expected the unknown NamespaceStatus as an object, got %+v", status)
+	}
+	if _, ok := swagger.Components.Schemas["io.k8s.api.apps.v1.DeploymentStatus"]; !ok {
+		t.Error("expected DeploymentStatus from the second page of schemas")
+	}
+}
+
+func TestGenerateHTTP(t *testing.T) {
+	source := generator.NewDirSource("../testdata/steve")
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-abc" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		doc, err := source.Get(fixtureURL + strings.TrimPrefix(r.URL.RequestURI(), "/v1"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(strings.Replace(string(doc), fixtureURL, srv.URL+"/v1", -1)))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "gen-api-docs-steve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	swagger, err := New(Options{
+		URL:    srv.URL + "/v1",
+		Token:  "token-abc",
+		Base:   "../data/steve.yml",
+		Output: dir,
+	}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := swagger.Paths["/namespaces/{name}"]; !ok {
+		t.Errorf("expected the namespaces from the second page, got %d paths", len(swagger.Paths))
+	}
+	if swagger.Servers[0].URL != "https://{hostname}/v1" {
+		t.Errorf("expected the Steve server, got %+v", swagger.Servers)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "swagger.json")); err != nil {
+		t.Errorf("expected swagger.json to be written - %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.json")); err == nil {
+		t.Error("expected no crawl report")
+	}
+}
+
+func TestTypeSchema(t *testing.T) {
+	g := New(Options{})
+	g.swagger = &openapi.OpenAPI{}
+	g.swagger.Components.Schemas = make(map[string]openapi.Schema)
+	g.schemas = map[string]Schema{"volume": {ID: "volume"}}
+
+	for typ, expected := range map[string]string{
+		"string":                     `{"type":"string"}`,
+		"password":                   `{"type":"string","format":"password"}`,
+		"intOrString":                `{"oneOf":[{"type":"string"},{"type":"integer"}]}`,
+		"reference[namespace]":       `{"type":"string"}`,
+		"array[map[string]]":         `{"type":"array","items":{"type":"object","additionalProperties":{"type":"string"}}}`,
+		"map[array[volume]]":         `{"type":"object","additionalProperties":{"type":"array","items":{"$ref":"#/components/schemas/volume"}}}`,
+		"array[reference[secret]]":   `{"type":"array","items":{"type":"string"}}`,
+		"io.k8s.api.core.v1.Missing": `{"type":"object"}`,
+	} {
+		out, err := json.Marshal(g.typeSchema(typ))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != expected {
+			t.Errorf("%s: expected %s, got %s", typ, expected, out)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package steve
+
+import (
+	"fmt"
+	neturl "net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rancher/gen-api-docs/generator"
+	openapi "github.com/rancher/gen-api-docs/openapi/v3.0.1"
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// collectionVerbs - collection methods for the Kubernetes verbs, when a schema lists none
+	collectionVerbs = map[string]string{"list": "GET", "create": "POST"}
+	// resourceVerbs - resource methods for the Kubernetes verbs, when a schema lists none
+	resourceVerbs = map[string]string{"get": "GET", "update": "PUT", "patch": "PATCH", "delete": "DELETE"}
+)
+
+// translateType - the collection and resource paths of a schema with a collection.
+// Namespaced types are also listed per namespace and their resources are
+// addressed by namespace and name.
+func (g *Generator) translateType(schema Schema) {
+	g.translateSchema(schema)
+	g.createCollectionSchema(schema)
+
+	col := g.collectionPath(schema)
+	collectionMethods := methods(schema.CollectionMethods, schema.Attributes.Verbs, collectionVerbs)
+	resourceMethods := methods(schema.ResourceMethods, schema.Attributes.Verbs, resourceVerbs)
+
+	// /{type}
+	colPathItem := openapi.PathItem{}
+	for _, method := range collectionMethods {
+		g.setOperation(&colPathItem, method, createCollection(method, schema))
+	}
+	g.swagger.Paths[col] = colPathItem
+
+	resourceParams := []openapi.Parameter{g.pathParameter("name", "Name of the resource")}
+	resourcePath := fmt.Sprintf("%s/{name}", col)
+	if schema.Attributes.Namespaced {
+		namespace := g.pathParameter("namespace", "Namespace of the resource")
+		resourceParams = append([]openapi.Parameter{namespace}, resourceParams...)
+		resourcePath = fmt.Sprintf("%s/{namespace}/{name}", col)
+
+		// /{type}/{namespace}
+		nsPathItem := openapi.PathItem{
+			Parameters: []openapi.Parameter{namespace},
+		}
+		for _, method := range collectionMethods {
+			g.setOperation(&nsPathItem, method, createCollection(method, schema))
+		}
+		g.swagger.Paths[fmt.Sprintf("%s/{namespace}", col)] = nsPathItem
+	}
+
+	// /{type}/{namespace}/{name} or /{type}/{name}
+	resourcePathItem := openapi.PathItem{
+		Parameters: resourceParams,
+	}
+	for _, method := range resourceMethods {
+		g.setOperation(&resourcePathItem, method, createResource(method, schema))
+	}
+	g.swagger.Paths[resourcePath] = resourcePathItem
+}
+
+func (g *Generator) setOperation(pathItem *openapi.PathItem, method string, op *openapi.Operation) {
+	switch method {
+	case "GET":
+		pathItem.Get = op
+	case "POST":
+		pathItem.Post = op
+	case "PUT":
+		pathItem.Put = op
+	case "PATCH":
+		pathItem.Patch = op
+	case "DELETE":
+		pathItem.Delete = op
+	default:
+		log.Error("Unknown Method: ", method)
+	}
+}
+
+// collectionPath - the collection link relative to the API root, /<id> without one
+func (g *Generator) collectionPath(schema Schema) string {
+	fallback := "/" + schema.ID
+	link, err := neturl.Parse(schema.Links["collection"])
+	if err != nil {
+		return fallback
+	}
+	base, err := neturl.Parse(g.opts.URL)
+	if err != nil {
+		return fallback
+	}
+	prefix := strings.TrimSuffix(base.Path, "/") + "/"
+	if !strings.HasPrefix(link.Path, prefix) {
+		return fallback
+	}
+	return "/" + strings.TrimPrefix(path.Clean(link.Path), prefix)
+}
+
+// methods - the listed methods, or the methods for the verbs when none are listed
+func methods(listed []string, verbs []string, verbMethods map[string]string) []string {
+	if len(listed) > 0 {
+		return listed
+	}
+	out := make([]string, 0)
+	for _, verb := range verbs {
+		if method, ok := verbMethods[verb]; ok {
+			out = append(out, method)
+		}
+	}
+	return out
+}
+
+func createCollection(method string, schema Schema) *openapi.Operation {
+	name := collectionName(schema)
+	op := &openapi.Operation{
+		Tags:        []string{schema.ID},
+		Description: fmt.Sprintf("`%s` Collection", name),
+		Responses:   make(map[string]openapi.Response),
+	}
+
+	switch method {
+	case "GET":
+		op.Parameters = listParameters()
+		op.Responses["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns list of '%s'", name),
+			Content:     jsonContent(name),
+		}
+	case "POST":
+		op.RequestBody = &openapi.RequestBody{
+			Description: fmt.Sprintf("Create a new `%s` object.", schema.ID),
+			Content:     jsonContent(schema.ID),
+			Required:    true,
+		}
+		op.Responses["201"] = openapi.Response{
+			Description: fmt.Sprintf("Returns new `%s` object.", schema.ID),
+			Content:     jsonContent(schema.ID),
+		}
+	}
+	return op
+}
+
+func createResource(method string, schema Schema) *openapi.Operation {
+	op := &openapi.Operation{
+		Tags:        []string{schema.ID},
+		Description: fmt.Sprintf("`%s` Resource", schema.ID),
+		Responses:   make(map[string]openapi.Response),
+	}
+
+	switch method {
+	case "GET":
+		op.Responses["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns '%s' object.", schema.ID),
+			Content:     jsonContent(schema.ID),
+		}
+	case "PUT":
+		op.RequestBody = &openapi.RequestBody{
+			Description: fmt.Sprintf("Update `%s` object.", schema.ID),
+			Content:     jsonContent(schema.ID),
+			Required:    true,
+		}
+		op.Responses["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns '%s' object.", schema.ID),
+			Content:     jsonContent(schema.ID),
+		}
+	case "PATCH":
+		op.RequestBody = &openapi.RequestBody{
+			Description: fmt.Sprintf("JSON merge patch of the `%s` object.", schema.ID),
+			Content: map[string]openapi.MediaType{
+				"application/merge-patch+json": {Schema: &openapi.Schema{Ref: ref(schema.ID)}},
+			},
+			Required: true,
+		}
+		op.Responses["200"] = openapi.Response{
+			Description: fmt.Sprintf("Returns '%s' object.", schema.ID),
+			Content:     jsonContent(schema.ID),
+		}
+	case "DELETE":
+		op.Responses["204"] = openapi.Response{
+			Description: "Delete Successful",
+		}
+	}
+	return op
+}
+
+// listParameters - the Kubernetes list options Steve passes through
+func listParameters() []openapi.Parameter {
+	parameters := make([]openapi.Parameter, 0)
+	for _, p := range []struct {
+		name, typ, description string
+	}{
+		{"limit", "integer", "Maximum number of resources to return"},
+		{"continue", "string", "Continue token from the pagination of the previous page"},
+		{"labelSelector", "string", "Only return resources with matching labels, e.g. `app=web`"},
+		{"fieldSelector", "string", "Only return resources with matching fields, e.g. `metadata.name=web`"},
+	} {
+		parameters = append(parameters, openapi.Parameter{
+			Name:        p.name,
+			In:          "query",
+			Description: p.description,
+			Schema: &openapi.Schema{
+				Type: p.typ,
+			},
+		})
+	}
+	return parameters
+}
+
+func (g *Generator) pathParameter(name string, description string) openapi.Parameter {
+	if _, ok := g.swagger.Components.Parameters[name]; !ok {
+		g.swagger.Components.Parameters[name] = openapi.Parameter{
+			Name:        name,
+			In:          "path",
+			Description: description,
+			Required:    true,
+			Schema: &openapi.Schema{
+				Type: "string",
+			},
+		}
+	}
+	return openapi.Parameter{
+		Ref: fmt.Sprintf("#/components/parameters/%s", name),
+	}
+}
+
+// createCollectionSchema - the list of a type, named after its plural name
+func (g *Generator) createCollectionSchema(schema Schema) {
+	g.swagger.Components.Schemas[collectionName(schema)] = openapi.Schema{
+		Type: "object",
+		AllOf: []openapi.Schema{
+			{Ref: ref("collection")},
+		},
+		Properties: map[string]openapi.Schema{
+			"data": {
+				Type:  "array",
+				Items: &openapi.Schema{Ref: ref(schema.ID)},
+			},
+		},
+	}
+}
+
+// translateSchema - add the schema and every schema its fields reference to the components
+func (g *Generator) translateSchema(schema Schema) {
+	if _, ok := g.swagger.Components.Schemas[schema.ID]; ok {
+		log.Debug(schema.ID, " Schema Already Exists")
+		return
+	}
+	// Placeholder so types referencing themselves don't recurse
+	g.swagger.Components.Schemas[schema.ID] = openapi.Schema{Type: "object"}
+
+	names := make([]string, 0, len(schema.ResourceFields))
+	for name := range schema.ResourceFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make(map[string]openapi.Schema)
+	required := make([]string, 0)
+	for _, name := range names {
+		field := schema.ResourceFields[name]
+		if field.Required {
+			required = append(required, name)
+		}
+		properties[name] = g.fieldSchema(field)
+	}
+
+	out := openapi.Schema{
+		Type:        "object",
+		Description: schema.Description,
+		Properties:  properties,
+		Required:    required,
+	}
+	if schema.Attributes.Kind != "" {
+		out.SetGroupVersionKind([]openapi.GroupVersionKind{{
+			Group:   schema.Attributes.Group,
+			Version: schema.Attributes.Version,
+			Kind:    schema.Attributes.Kind,
+		}})
+		scope := "Cluster scoped"
+		if schema.Attributes.Namespaced {
+			scope = "Namespaced"
+		}
+		out.Description = strings.TrimSpace(fmt.Sprintf("%s\n\n%s `%s` `%s`.", schema.Description, scope, groupVersion(schema.Attributes), schema.Attributes.Kind))
+	}
+	g.swagger.Components.Schemas[schema.ID] = out
+}
+
+// fieldSchema - a resource field, references to other schemas carry their
+// description and flags next to an allOf of the $ref
+func (g *Generator) fieldSchema(field norman.Field) openapi.Schema {
+	p := g.typeSchema(field.Type)
+	p.Description = field.Description
+	p.Default = generator.TypedValue(field.Default, p.Type)
+	p.Nullable = field.Nullable
+	p.ReadOnly = !field.Create && !field.Update
+	if p.Type == "string" || p.Type == "integer" || p.Type == "number" {
+		p.Enum = generator.TypedValues(field.Options, p.Type)
+	}
+	if p.Type == "integer" || p.Type == "number" {
+		p.Minimum = generator.Number(field.Min)
+		p.Maximum = generator.Number(field.Max)
+	}
+	if p.Type == "string" {
+		p.MinLength = field.MinLength
+		p.MaxLength = field.MaxLength
+	}
+	return p
+}
+
+// typeSchema - the OpenAPI schema for a norman field type, e.g. array[map[string]]
+func (g *Generator) typeSchema(typ string) openapi.Schema {
+	switch {
+	case strings.HasPrefix(typ, "array[") && strings.HasSuffix(typ, "]"):
+		items := g.typeSchema(typ[len("array[") : len(typ)-1])
+		return openapi.Schema{Type: "array", Items: &items}
+	case strings.HasPrefix(typ, "map[") && strings.HasSuffix(typ, "]"):
+		values := g.typeSchema(typ[len("map[") : len(typ)-1])
+		return openapi.Schema{Type: "object", AdditionalProperties: &values}
+	case strings.HasPrefix(typ, "reference[") && strings.HasSuffix(typ, "]"):
+		return openapi.Schema{Type: "string"}
+	}
+
+	switch typ {
+	case "string", "enum", "dnsLabel", "dnsLabelRestricted", "hostname":
+		return openapi.Schema{Type: "string"}
+	case "password":
+		return openapi.Schema{Type: "string", Format: "password"}
+	case "base64":
+		return openapi.Schema{Type: "string", Format: "byte"}
+	case "date":
+		return openapi.Schema{Type: "string", Format: "date-time"}
+	case "boolean":
+		return openapi.Schema{Type: "boolean"}
+	case "int":
+		return openapi.Schema{Type: "integer"}
+	case "float":
+		return openapi.Schema{Type: "number", Format: "double"}
+	case "intOrString":
+		return openapi.Schema{OneOf: []openapi.Schema{{Type: "string"}, {Type: "integer"}}}
+	case "json", "object", "":
+		return openapi.Schema{Type: "object"}
+	}
+
+	schema, ok := g.schemas[typ]
+	if !ok {
+		log.Warnf("Unknown type %s, documented as an object", typ)
+		return openapi.Schema{Type: "object"}
+	}
+	g.translateSchema(schema)
+	return openapi.Schema{Ref: ref(schema.ID)}
+}
+
+// collectionName - name of the collection schema, the plural name or <id>s
+func collectionName(schema Schema) string {
+	if schema.PluralName != "" {
+		return schema.PluralName
+	}
+	return schema.ID + "s"
+}
+
+// groupVersion - apps/v1, or just v1 for the core group
+func groupVersion(attributes Attributes) string {
+	if attributes.Group == "" {
+		return attributes.Version
+	}
+	return fmt.Sprintf("%s/%s", attributes.Group, attributes.Version)
+}
+
+func jsonContent(name string) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{
+		"application/json": {Schema: &openapi.Schema{Ref: ref(name)}},
+	}
+}
+
+func ref(name string) string {
+	return fmt.Sprintf("#/components/schemas/%s", name)
+}
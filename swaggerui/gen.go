@@ -0,0 +1,56 @@
+//go:build ignore
+// +build ignore
+
+// gen writes assets.go from the package directory of a swagger-ui-dist release.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// files - copied from the release when it has them, the licenses go along with the code
+var files = []string{"swagger-ui.css", "swagger-ui-bundle.js", "swagger-ui-standalone-preset.js", "LICENSE", "NOTICE"}
+
+func main() {
+	dist := flag.String("dist", "", "package directory of the swagger-ui-dist release")
+	version := flag.String("version", "", "version of the swagger-ui-dist release")
+	out := flag.String("out", "assets.go", "file to write")
+	flag.Parse()
+	if *dist == "" || *version == "" {
+		log.Fatal("Set -dist and -version, scripts/swagger-ui downloads the release")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by go run gen.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package swaggerui\n\n")
+	fmt.Fprintf(&buf, "// Version - the swagger-ui-dist release the assets are from\n")
+	fmt.Fprintf(&buf, "const Version = %q\n\n", *version)
+	fmt.Fprintf(&buf, "var assets = map[string]string{\n")
+	for _, name := range files {
+		content, err := ioutil.ReadFile(filepath.Join(*dist, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(&buf, "%q: %q,\n", name, content)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
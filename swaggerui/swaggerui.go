@@ -0,0 +1,38 @@
+// Package swaggerui serves the swagger-ui-dist assets compiled into the binary.
+// assets.go is generated from a swagger-ui-dist release by scripts/swagger-ui.
+package swaggerui
+
+//go:generate go run gen.go -dist $SWAGGER_UI_DIST -version $SWAGGER_UI_VERSION
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Files - the swagger-ui-dist files loaded by the page hosting the UI
+var Files = []string{"swagger-ui.css", "swagger-ui-bundle.js", "swagger-ui-standalone-preset.js"}
+
+// Handler - the assets by file name, an error when one of the Files
+// wasn't generated into the binary
+func Handler() (http.Handler, error) {
+	return handler(assets)
+}
+
+func handler(files map[string]string) (http.Handler, error) {
+	for _, name := range Files {
+		if _, ok := files[name]; !ok {
+			return nil, fmt.Errorf("swagger-ui %s isn't in the binary, run scripts/swagger-ui and rebuild", name)
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		content, ok := files[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, name, time.Time{}, strings.NewReader(content))
+	}), nil
+}
@@ -0,0 +1,48 @@
+package swaggerui
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	files := map[string]string{
+		"swagger-ui.css":                  "body {}",
+		"swagger-ui-bundle.js":            "var SwaggerUIBundle;",
+		"swagger-ui-standalone-preset.js": "var SwaggerUIStandalonePreset;",
+	}
+	h, err := handler(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expected := range map[string]string{
+		"/swagger-ui-bundle.js": "javascript",
+		"/swagger-ui.css":       "text/css",
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", name, nil))
+		body, _ := ioutil.ReadAll(w.Body)
+		if w.Code != 200 || string(body) != files[strings.TrimPrefix(name, "/")] {
+			t.Errorf("%s: expected the asset, got %d %q", name, w.Code, body)
+		}
+		if typ := w.Header().Get("Content-Type"); !strings.Contains(typ, expected) {
+			t.Errorf("%s: expected %s, got %s", name, expected, typ)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/index.html", nil))
+	if w.Code != 404 {
+		t.Errorf("expected files that aren't assets to be missing, got %d", w.Code)
+	}
+}
+
+func TestHandlerWithoutAssets(t *testing.T) {
+	_, err := handler(map[string]string{"swagger-ui.css": "body {}"})
+	if err == nil || !strings.Contains(err.Error(), "swagger-ui-bundle.js") {
+		t.Errorf("expected an error naming the missing bundle, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rancher/gen-api-docs/generator"
+	norman "github.com/rancher/norman/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultWatchInterval = time.Minute
+
+// schemaDiff - what changed between two fetches of the schemas, each schema
+// by its root and ID, e.g. /v3/schemas/cluster
+type schemaDiff struct {
+	Added   []string       `json:"added,omitempty"`
+	Removed []string       `json:"removed,omitempty"`
+	Changed []schemaChange `json:"changed,omitempty"`
+}
+
+// schemaChange - a schema that exists in both fetches but differs
+type schemaChange struct {
+	ID            string   `json:"id"`
+	AddedFields   []string `json:"addedFields,omitempty"`
+	RemovedFields []string `json:"removedFields,omitempty"`
+	ChangedFields []string `json:"changedFields,omitempty"`
+	// Other - something besides the resource fields changed, e.g. actions or filters
+	Other bool `json:"other,omitempty"`
+}
+
+// watcher - polls the schemas and regenerates when their fingerprint changes
+type watcher struct {
+	// schemas - the schemas of every API root, by the path of the root
+	schemas  func() (map[string][]norman.Schema, error)
+	interval time.Duration
+	// regenerate - run for every change, including the first fetch
+	regenerate func(diff *schemaDiff) error
+
+	fingerprint string
+	last        map[string]norman.Schema
+}
+
+// watch - regenerate the docs every time the schemas change, never returns
+func watch(g *generator.Generator, interval time.Duration) {
+	w := &watcher{
+		schemas:  g.AllSchemas,
+		interval: interval,
+		regenerate: func(diff *schemaDiff) error {
+			_, _, err := g.Generate()
+			if err != nil {
+				return err
+			}
+			return diff.write(filepath.Join(outputDir, "schema-diff.json"))
+		},
+	}
+	log.Infof("Watching schemas every %s", interval)
+	w.run(nil)
+}
+
+// run - check the schemas every interval until stop is closed
+func (w *watcher) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.check(); err != nil {
+			log.Errorf("Failed to check schemas - %v", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check - fetch the schemas and regenerate when they changed.
+// Schemas are keyed by their root, /v3/schemas/cluster, since the same ID
+// can be in more than one root. Returns nil when the fingerprint is unchanged.
+func (w *watcher) check() (*schemaDiff, error) {
+	roots, err := w.schemas()
+	if err != nil {
+		return nil, err
+	}
+	schemas := make(map[string]norman.Schema)
+	for root, list := range roots {
+		addSchemas(schemas, root, list)
+	}
+	fingerprint := fingerprintSchemas(schemas)
+	if fingerprint == w.fingerprint {
+		log.Debugf("Schemas unchanged, fingerprint %s", fingerprint)
+		return nil, nil
+	}
+
+	diff := diffSchemas(w.last, schemas)
+	if w.last == nil {
+		log.Infof("Fetched %d schemas, fingerprint %s", len(schemas), fingerprint)
+	} else {
+		log.Infof("Schemas changed, fingerprint %s:\n%s", fingerprint, diff)
+	}
+
+	err = w.regenerate(diff)
+	if err != nil {
+		return diff, err
+	}
+	// Generating finds the public and scoped roots, remember their schemas too
+	if after, err := w.schemas(); err == nil {
+		for root, list := range after {
+			if _, ok := roots[root]; !ok {
+				addSchemas(schemas, root, list)
+			}
+		}
+		fingerprint = fingerprintSchemas(schemas)
+	}
+	// Only remember the schemas once the docs are built so failures are retried
+	w.fingerprint = fingerprint
+	w.last = schemas
+	return diff, nil
+}
+
+// addSchemas - the schemas of root by root and ID
+func addSchemas(schemas map[string]norman.Schema, root string, list []norman.Schema) {
+	for _, schema := range list {
+		// Links carry the server URL and don't change the docs
+		schema.Links = nil
+		schemas[fmt.Sprintf("%s/schemas/%s", root, schema.ID)] = schema
+	}
+}
+
+// fingerprintSchemas - sha256 over the schemas in key order
+func fingerprintSchemas(schemas map[string]norman.Schema) string {
+	ids := make([]string, 0, len(schemas))
+	for id := range schemas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := sha256.New()
+	for _, id := range ids {
+		out, _ := json.Marshal(schemas[id])
+		fmt.Fprintf(h, "%s\n%s\n", id, out)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+func diffSchemas(before, after map[string]norman.Schema) *schemaDiff {
+	diff := &schemaDiff{}
+	for id, schema := range after {
+		oldSchema, ok := before[id]
+		if !ok {
+			diff.Added = append(diff.Added, id)
+			continue
+		}
+		if change, ok := diffSchema(id, oldSchema, schema); ok {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].ID < diff.Changed[j].ID
+	})
+	return diff
+}
+
+func diffSchema(id string, before, after norman.Schema) (schemaChange, bool) {
+	change := schemaChange{ID: id}
+	for name, field := range after.ResourceFields {
+		oldField, ok := before.ResourceFields[name]
+		switch {
+		case !ok:
+			change.AddedFields = append(change.AddedFields, name)
+		case !reflect.DeepEqual(oldField, field):
+			change.ChangedFields = append(change.ChangedFields, name)
+		}
+	}
+	for name := range before.ResourceFields {
+		if _, ok := after.ResourceFields[name]; !ok {
+			change.RemovedFields = append(change.RemovedFields, name)
+		}
+	}
+	sort.Strings(change.AddedFields)
+	sort.Strings(change.RemovedFields)
+	sort.Strings(change.ChangedFields)
+
+	before.ResourceFields, after.ResourceFields = nil, nil
+	change.Other = !reflect.DeepEqual(before, after)
+
+	changed := change.Other || len(change.AddedFields) > 0 || len(change.RemovedFields) > 0 || len(change.ChangedFields) > 0
+	return change, changed
+}
+
+func (d *schemaDiff) write(file string) error {
+	out, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, out, 0644)
+}
+
+// String - one line per added, removed or changed schema
+func (d *schemaDiff) String() string {
+	lines := make([]string, 0)
+	for _, id := range d.Added {
+		lines = append(lines, fmt.Sprintf("+ %s", id))
+	}
+	for _, id := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %s", id))
+	}
+	for _, change := range d.Changed {
+		details := make([]string, 0)
+		if len(change.AddedFields) > 0 {
+			details = append(details, fmt.Sprintf("added fields %s", strings.Join(change.AddedFields, ", ")))
+		}
+		if len(change.RemovedFields) > 0 {
+			details = append(details, fmt.Sprintf("removed fields %s", strings.Join(change.RemovedFields, ", ")))
+		}
+		if len(change.ChangedFields) > 0 {
+			details = append(details, fmt.Sprintf("changed fields %s", strings.Join(change.ChangedFields, ", ")))
+		}
+		if change.Other {
+			details = append(details, "schema changed")
+		}
+		lines = append(lines, fmt.Sprintf("~ %s: %s", change.ID, strings.Join(details, "; ")))
+	}
+	if len(lines) == 0 {
+		return "no changes"
+	}
+	return strings.Join(lines, "\n")
+}
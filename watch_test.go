@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	norman "github.com/rancher/norman/types"
+)
+
+func TestWatch(t *testing.T) {
+	srv := newFakeRancher(t)
+	defer srv.Close()
+
+	diffs := make([]*schemaDiff, 0)
+	g := newGenerator(t, srv)
+	w := &watcher{
+		schemas:  g.AllSchemas,
+		interval: time.Millisecond,
+		regenerate: func(diff *schemaDiff) error {
+			diffs = append(diffs, diff)
+			_, _, err := g.Generate()
+			return err
+		},
+	}
+
+	check := func() *schemaDiff {
+		t.Helper()
+		diff, err := w.check()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diff
+	}
+
+	if diff := check(); diff == nil || !contains(diff.Added, "/v3/schemas/cluster") || len(diff.Changed) != 0 {
+		t.Fatalf("expected every schema to be added on the first check, got %+v", diff)
+	}
+	if diff := check(); diff != nil {
+		t.Errorf("expected no changes once the other roots are found, got %s", diff)
+	}
+
+	cluster, _ := srv.Schema("/v3", "cluster")
+	fields := make(map[string]norman.Field)
+	for name, field := range cluster.ResourceFields {
+		fields[name] = field
+	}
+	fields["fleetWorkspace"] = norman.Field{Type: "string", Create: true, Update: true}
+	name := fields["name"]
+	name.Required = false
+	fields["name"] = name
+	delete(fields, "nodeCount")
+	cluster.ResourceFields = fields
+	srv.SetSchema("/v3", cluster)
+	srv.SetSchema("/v3", norman.Schema{ID: "nodePool", PluralName: "nodePools"})
+	srv.RemoveSchema("/v3", "project")
+
+	namespace, _ := srv.Schema("/v3/cluster/c-1", "namespace")
+	fields = make(map[string]norman.Field)
+	for name, field := range namespace.ResourceFields {
+		fields[name] = field
+	}
+	fields["resourceQuota"] = norman.Field{Type: "string", Create: true}
+	namespace.ResourceFields = fields
+	srv.SetSchema("/v3/cluster/c-1", namespace)
+
+	diff := check()
+	if diff == nil {
+		t.Fatal("expected the schema changes to be found")
+	}
+	expected := strings.Join([]string{
+		"+ /v3/schemas/nodePool",
+		"- /v3/schemas/project",
+		"~ /v3/cluster/c-1/schemas/namespace: added fields resourceQuota",
+		"~ /v3/schemas/cluster: added fields fleetWorkspace; removed fields nodeCount; changed fields name",
+	}, "\n")
+	if diff.String() != expected {
+		t.Errorf("expected diff:\n%s\ngot:\n%s", expected, diff)
+	}
+	if len(diffs) != 2 {
+		t.Errorf("expected to regenerate twice, got %d", len(diffs))
+	}
+
+	if diff := check(); diff != nil {
+		t.Errorf("expected no changes after regenerating, got %s", diff)
+	}
+}